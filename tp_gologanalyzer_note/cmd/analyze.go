@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/axellelanca/go_loganizer/internal/analyzer"
 	"github.com/axellelanca/go_loganizer/internal/config"
@@ -13,13 +18,29 @@ import (
 var (
 	configPath string
 	outputPath string
+	workers    int
+	timeout    time.Duration
+	statePath  string
+	format     string
+	filter     config.Filter
+	since      string
+	until      string
+	cluster    bool
+	timeline   string
+	baseline   string
+	sigma      float64
 )
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze",
 	Short: "Analyse des fichiers de logs en parallèle",
 	Long: `Analyse plusieurs fichiers de logs de façon concurrente.
-			Prend un fichier de config JSON en entrée et peut exporter les résultats dans un fichier JSON.
+			Prend un fichier de config (JSON, YAML ou TOML) en entrée et peut exporter les résultats
+			(json, ndjson, csv, markdown, html, junit).
+			Codes de sortie, du plus prioritaire au moins prioritaire:
+			130 interruption, puis la pire erreur de fichier (9 introuvable, 8 accès refusé,
+			7 répertoire, 6 lecture, 5 timeout, 4 parsing), puis 3 si une alerte s'est
+			déclenchée, sinon 0. Une erreur d'usage, de config ou d'export sort avec 1.
 			Exemple:
   			loganalyzer analyze -c config.json -o rapport.json`,
 	Run: executeAnalysis,
@@ -29,28 +50,141 @@ func executeAnalysis(cmd *cobra.Command, args []string) {
 	if configPath == "" {
 		fmt.Println("Erreur: le flag --config (-c) est obligatoire")
 		cmd.Help()
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	fmt.Printf("Début de l'analyse avec: %s\n", configPath)
 
 	// Chargement config
-	logConfigs, err := config.LoadConfig(configPath)
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
 		fmt.Printf("Erreur config: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
+	applySettings(cmd, cfg.Settings)
 
-	fmt.Printf("Config chargée: %d fichiers de logs\n", len(logConfigs))
+	// Sous-ensemble demandé sur la ligne de commande
+	if err := filter.Validate(); err != nil {
+		fmt.Printf("Erreur filtre: %v\n", err)
+		os.Exit(exitError)
+	}
+	logConfigs := filter.Apply(cfg.Logs)
+	if len(logConfigs) == 0 {
+		fmt.Println("Erreur: aucun log ne correspond aux filtres")
+		os.Exit(exitError)
+	}
+
+	if workers < 0 {
+		fmt.Println("Erreur: --workers doit être positif")
+		os.Exit(exitError)
+	}
+	if timeout < 0 {
+		fmt.Println("Erreur: --timeout doit être positif")
+		os.Exit(exitError)
+	}
+	if _, ok := config.TimelineBucket(timeline); timeline != "" && !ok {
+		fmt.Printf("Erreur: --timeline inconnu %q (minute, hour ou day)\n", timeline)
+		os.Exit(exitError)
+	}
+	if sigma <= 0 {
+		fmt.Println("Erreur: --sigma doit être positif")
+		os.Exit(exitError)
+	}
+	if format != "" && !reporter.IsFormat(format) {
+		fmt.Printf("Erreur: format inconnu %q (formats: %s)\n", format, strings.Join(reporter.Formats(), ", "))
+		os.Exit(exitError)
+	}
+
+	// Fenêtre de dates, relative à maintenant
+	window, err := parseWindow(since, until, time.Now())
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		os.Exit(exitError)
+	}
+	if !window.IsZero() && statePath != "" {
+		fmt.Println("Erreur: --since/--until et --state ne peuvent pas être utilisés ensemble")
+		os.Exit(exitError)
+	}
+
+	fmt.Printf("Config chargée: %d fichiers de logs, %d règles d'alerte\n", len(logConfigs), len(cfg.Rules))
+	if !filter.IsEmpty() {
+		fmt.Printf("Filtres: %d/%d fichiers retenus\n", len(logConfigs), len(cfg.Logs))
+	}
+
+	// Baseline de référence pour la détection d'anomalies
+	var reference *analyzer.Baseline
+	if baseline != "" {
+		reference, err = analyzer.LoadBaseline(baseline)
+		if err != nil {
+			fmt.Printf("Erreur: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	// Analyse incrémentale si un fichier d'état est donné
+	var checkpoints *analyzer.CheckpointStore
+	if statePath != "" {
+		checkpoints, err = analyzer.LoadCheckpoints(statePath)
+		if err != nil {
+			fmt.Printf("Erreur état: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	// Ctrl-C / SIGTERM annulent l'analyse proprement
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Corrélation entre fichiers si la config la demande
+	var correlator *analyzer.Correlator
+	if cfg.Correlation != nil {
+		correlator = analyzer.NewCorrelator(*cfg.Correlation)
+	}
 
 	// Lancement analyse en parallèle
 	fmt.Println("Analyse en cours...")
-	results := analyzer.AnalyzeLogsConcurrently(logConfigs)
+	startedAt := time.Now()
+	results := analyzer.AnalyzeLogsConcurrently(ctx, logConfigs, analyzer.Options{
+		Workers:     workers,
+		Timeout:     timeout,
+		Checkpoints: checkpoints,
+		Window:      window,
+		Cluster:     cluster,
+		Timeline:    timeline,
+		Correlator:  correlator,
+	})
+	finishedAt := time.Now()
+	var correlation *config.CorrelationReport
+	if correlator != nil {
+		correlation = correlator.Report()
+	}
+	interrupted := ctx.Err() != nil
+	if interrupted {
+		fmt.Println("Analyse interrompue, résultats partiels")
+	}
+
+	if checkpoints != nil {
+		if err := checkpoints.Save(); err != nil {
+			fmt.Printf("Erreur sauvegarde état: %v\n", err)
+		}
+	}
+
+	// Écarts par rapport à la baseline
+	if reference != nil {
+		missing := reference.Detect(results, sigma)
+		if len(missing) > 0 {
+			fmt.Printf("Pas de baseline pour: %s\n", strings.Join(missing, ", "))
+		}
+	}
+
+	// Règles d'alerte sur les résultats
+	alerts := analyzer.EvaluateRules(cfg.Rules, results, finishedAt)
 
 	// Affichage résultats
-	reporter.PrintResults(results)
+	reporter.PrintResults(results, alerts)
+	reporter.PrintCorrelation(correlation)
 
-	// Export JSON si demandé
+	// Export si demandé
 	if outputPath != "" {
 		// Nom avec timestamp si c'est un nom générique
 		finalOutputPath := outputPath
@@ -59,14 +193,69 @@ func executeAnalysis(cmd *cobra.Command, args []string) {
 		}
 
 		fmt.Printf("Export vers: %s\n", finalOutputPath)
-		if err := reporter.ExportResults(results, finalOutputPath); err != nil {
+		report := reporter.NewReport(results, alerts, reporter.RunInfo{
+			StartedAt:   startedAt,
+			FinishedAt:  finishedAt,
+			ToolVersion: Version,
+			ConfigPath:  configPath,
+			Interrupted: interrupted,
+			Filters:     filter,
+			Window:      window,
+			Correlation: correlation,
+		})
+		if err := reporter.ExportReport(report, finalOutputPath, format); err != nil {
 			fmt.Printf("Erreur export: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitError)
 		}
 		fmt.Printf("Export réussi!\n")
 	}
 
+	if interrupted {
+		stop()
+		os.Exit(exitInterrupted)
+	}
 	fmt.Println("Analyse terminée!")
+	if code := resultsExitCode(results); code != exitOK {
+		os.Exit(code)
+	}
+	if len(alerts) > 0 {
+		os.Exit(exitAlerts)
+	}
+}
+
+// parseWindow lit --since et --until
+func parseWindow(since, until string, now time.Time) (config.TimeWindow, error) {
+	var window config.TimeWindow
+	var err error
+	if window.Since, err = config.ParseWindowBound(since, now); err != nil {
+		return window, fmt.Errorf("--since: %w", err)
+	}
+	if window.Until, err = config.ParseWindowBound(until, now); err != nil {
+		return window, fmt.Errorf("--until: %w", err)
+	}
+	if !window.Since.IsZero() && !window.Until.IsZero() && window.Until.Before(window.Since) {
+		return window, fmt.Errorf("--until (%s) est avant --since (%s)",
+			window.Until.Format(time.RFC3339), window.Since.Format(time.RFC3339))
+	}
+	return window, nil
+}
+
+// applySettings reprend les réglages globaux de la config pour les flags
+// qui n'ont pas été donnés sur la ligne de commande
+func applySettings(cmd *cobra.Command, settings config.Settings) {
+	flags := cmd.Flags()
+	if !flags.Changed("workers") && settings.Workers != 0 {
+		workers = settings.Workers
+	}
+	if !flags.Changed("timeout") && settings.Timeout != 0 {
+		timeout = time.Duration(settings.Timeout)
+	}
+	if !flags.Changed("output") && settings.Output != "" {
+		outputPath = settings.Output
+	}
+	if !flags.Changed("format") && settings.Format != "" {
+		format = settings.Format
+	}
 }
 
 func init() {
@@ -74,9 +263,35 @@ func init() {
 
 	// Flags
 	analyzeCmd.Flags().StringVarP(&configPath, "config", "c", "", 
-		"Fichier de config JSON (obligatoire)")
+		"Fichier de config JSON, YAML ou TOML (obligatoire)")
 	analyzeCmd.Flags().StringVarP(&outputPath, "output", "o", "", 
-		"Fichier de sortie JSON (optionnel)")
+		"Fichier de sortie (optionnel), format déduit de l'extension")
+	analyzeCmd.Flags().IntVarP(&workers, "workers", "w", 0,
+		"Nombre de fichiers analysés en parallèle (0 = nombre de CPU)")
+	analyzeCmd.Flags().DurationVarP(&timeout, "timeout", "t", 0,
+		"Durée max d'analyse par fichier, ex: 30s (0 = aucune)")
+	analyzeCmd.Flags().StringVarP(&format, "format", "f", "",
+		"Format d'export: json, ndjson, csv, markdown, html, junit (défaut: selon l'extension)")
+	analyzeCmd.Flags().StringVar(&statePath, "state", "",
+		"Fichier d'état pour l'analyse incrémentale (offsets par ID de log)")
+	analyzeCmd.Flags().StringSliceVar(&filter.IDs, "only", nil,
+		"N'analyser que ces IDs, globs séparés par des virgules (ex: web-*,db-*)")
+	analyzeCmd.Flags().StringSliceVar(&filter.Tags, "tag", nil,
+		"N'analyser que les logs ayant un de ces tags")
+	analyzeCmd.Flags().StringSliceVar(&filter.Types, "type", nil,
+		"N'analyser que les logs de ces types")
+	analyzeCmd.Flags().StringVar(&since, "since", "",
+		"Ne compter que les lignes datées après: durée (2h, 7d) ou date (2025-09-24 14:00)")
+	analyzeCmd.Flags().StringVar(&until, "until", "",
+		"Ne compter que les lignes datées avant: durée (30m) ou date")
+	analyzeCmd.Flags().BoolVar(&cluster, "cluster", false,
+		"Regrouper les messages par motif pour tous les logs (sinon \"cluster\": true dans la config)")
+	analyzeCmd.Flags().StringVar(&timeline, "timeline", "",
+		"Histogramme d'activité par minute, hour ou day (sinon \"timeline\" dans la config)")
+	analyzeCmd.Flags().StringVar(&baseline, "baseline", "",
+		"Fichier de baseline (loganalyzer baseline save) pour signaler les anomalies")
+	analyzeCmd.Flags().Float64Var(&sigma, "sigma", 3,
+		"Écart à la baseline, en écarts-types, au-delà duquel une métrique est anormale")
 	
 	// Config obligatoire
 	analyzeCmd.MarkFlagRequired("config")
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/analyzer"
+	"github.com/axellelanca/go_loganizer/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	baselinePath    string
+	baselineWorkers int
+	baselineTimeout time.Duration
+	baselineFilter  config.Filter
+	baselineSince   string
+	baselineUntil   string
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Gère la baseline utilisée par analyze --baseline",
+}
+
+var baselineSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Enregistre les statistiques de référence des logs",
+	Long: `Analyse les logs de la config et enregistre pour chacun le volume de lignes
+			par heure, la part d'erreurs et la répartition des codes HTTP (moyenne et
+			écart-type heure par heure). Les autres logs déjà présents dans le fichier
+			sont conservés.
+			Exemple:
+  			loganalyzer baseline save -c config.json -b baseline.json --since 7d`,
+	Run: executeBaselineSave,
+}
+
+func executeBaselineSave(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Erreur config: %v\n", err)
+		os.Exit(exitError)
+	}
+	if err := baselineFilter.Validate(); err != nil {
+		fmt.Printf("Erreur filtre: %v\n", err)
+		os.Exit(exitError)
+	}
+	logConfigs := baselineFilter.Apply(cfg.Logs)
+	if len(logConfigs) == 0 {
+		fmt.Println("Erreur: aucun log ne correspond aux filtres")
+		os.Exit(exitError)
+	}
+	window, err := parseWindow(baselineSince, baselineUntil, time.Now())
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	// Mêmes réglages globaux qu'analyze si les flags ne sont pas donnés
+	if !cmd.Flags().Changed("workers") {
+		baselineWorkers = cfg.Settings.Workers
+	}
+	if !cmd.Flags().Changed("timeout") {
+		baselineTimeout = time.Duration(cfg.Settings.Timeout)
+	}
+
+	// Les statistiques sont calculées heure par heure
+	for i := range logConfigs {
+		logConfigs[i].Timeline = analyzer.BaselineBucket
+	}
+
+	baseline := analyzer.NewBaseline()
+	if _, err := os.Stat(baselinePath); err == nil {
+		if baseline, err = analyzer.LoadBaseline(baselinePath); err != nil {
+			fmt.Printf("Erreur: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Calcul de la baseline sur %d fichiers...\n", len(logConfigs))
+	results := analyzer.AnalyzeLogsConcurrently(ctx, logConfigs, analyzer.Options{
+		Workers: baselineWorkers,
+		Timeout: baselineTimeout,
+		Window:  window,
+	})
+	if ctx.Err() != nil {
+		fmt.Println("Analyse interrompue, baseline non enregistrée")
+		stop()
+		os.Exit(exitInterrupted)
+	}
+
+	skipped := baseline.Record(results, time.Now())
+	for _, result := range results {
+		if log, ok := baseline.Logs[result.LogID]; ok && log.SavedAt.Equal(baseline.UpdatedAt) {
+			fmt.Printf("[%s] %d heures, %.1f lignes/h (± %.1f), %.2f%% d'erreurs\n",
+				result.LogID, log.Hours, log.Volume.Mean, log.Volume.StdDev, log.ErrorRate.Mean*100)
+		}
+	}
+	for _, id := range skipped {
+		fmt.Printf("[%s] ignoré: échec ou aucune ligne datée\n", id)
+	}
+
+	if err := baseline.Save(baselinePath); err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		os.Exit(exitError)
+	}
+	fmt.Printf("Baseline enregistrée: %s (%d logs)\n", baselinePath, len(baseline.Logs))
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineSaveCmd)
+
+	baselineSaveCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Fichier de config JSON, YAML ou TOML (obligatoire)")
+	baselineSaveCmd.Flags().StringVarP(&baselinePath, "baseline", "b", "baseline.json",
+		"Fichier de baseline à créer ou mettre à jour")
+	baselineSaveCmd.Flags().IntVarP(&baselineWorkers, "workers", "w", 0,
+		"Nombre de fichiers analysés en parallèle (0 = nombre de CPU)")
+	baselineSaveCmd.Flags().DurationVarP(&baselineTimeout, "timeout", "t", 0,
+		"Durée max d'analyse par fichier (0 = aucune)")
+	baselineSaveCmd.Flags().StringSliceVar(&baselineFilter.IDs, "only", nil,
+		"N'enregistrer que ces IDs (globs séparés par des virgules)")
+	baselineSaveCmd.Flags().StringSliceVar(&baselineFilter.Tags, "tag", nil,
+		"N'enregistrer que les logs ayant un de ces tags")
+	baselineSaveCmd.Flags().StringSliceVar(&baselineFilter.Types, "type", nil,
+		"N'enregistrer que les logs de ces types")
+	baselineSaveCmd.Flags().StringVar(&baselineSince, "since", "",
+		"Période de référence: lignes datées après (durée ou date)")
+	baselineSaveCmd.Flags().StringVar(&baselineUntil, "until", "",
+		"Période de référence: lignes datées avant (durée ou date)")
+
+	baselineSaveCmd.MarkFlagRequired("config")
+}
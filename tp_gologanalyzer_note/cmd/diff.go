@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/axellelanca/go_loganizer/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat         string
+	diffMaxRegressions int
+	diffErrorTolerance int
+	diffShowAll        bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <ancien.json> <nouveau.json>",
+	Short: "Compare deux rapports d'analyse",
+	Long: `Compare deux rapports JSON exportés par analyze: logs apparus ou disparus,
+			changements de status, évolution des erreurs et des tailles.
+			Sort avec le code 2 si le nombre de régressions dépasse --max-regressions.
+			Exemple:
+  			loganalyzer diff rapport_hier.json rapport.json --max-regressions 0`,
+	Args: cobra.ExactArgs(2),
+	Run:  executeDiff,
+}
+
+func executeDiff(cmd *cobra.Command, args []string) {
+	if diffFormat != "text" && diffFormat != "json" {
+		fmt.Printf("Erreur: format inconnu %q (text ou json)\n", diffFormat)
+		os.Exit(exitError)
+	}
+
+	oldReport, err := reporter.LoadReport(args[0])
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		os.Exit(exitError)
+	}
+	newReport, err := reporter.LoadReport(args[1])
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	diff := reporter.CompareReports(oldReport, newReport, diffErrorTolerance)
+	if diffFormat == "json" {
+		if err := reporter.WriteDiffJSON(os.Stdout, diff); err != nil {
+			fmt.Printf("Erreur: %v\n", err)
+			os.Exit(exitError)
+		}
+	} else {
+		reporter.PrintDiff(os.Stdout, diff, diffShowAll)
+	}
+
+	if diffMaxRegressions >= 0 && diff.Regressions > diffMaxRegressions {
+		os.Exit(exitRegressions)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text",
+		"Format de sortie: text ou json")
+	diffCmd.Flags().IntVar(&diffMaxRegressions, "max-regressions", 0,
+		"Nombre de régressions toléré avant de sortir en erreur (-1 = jamais)")
+	diffCmd.Flags().IntVar(&diffErrorTolerance, "error-tolerance", 0,
+		"Hausse du nombre d'erreurs tolérée avant de compter une régression")
+	diffCmd.Flags().BoolVarP(&diffShowAll, "all", "a", false,
+		"Afficher aussi les logs inchangés")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import "github.com/axellelanca/go_loganizer/internal/config"
+
+// Codes de sortie des commandes
+const (
+	exitOK          = 0
+	exitError       = 1   // usage, config, export...
+	exitRegressions = 2   // diff: trop de régressions
+	exitAlerts      = 3   // analyze: au moins une règle d'alerte déclenchée
+	exitInterrupted = 130 // Ctrl-C / SIGTERM
+)
+
+// analyze: un code par classe d'erreur de fichier, le plus grave l'emporte
+const (
+	exitParseError       = 4 // aucune ligne reconnue, archive illisible
+	exitTimeout          = 5 // délai dépassé
+	exitIOError          = 6 // erreur de lecture
+	exitIsDirectory      = 7 // chemin qui est un répertoire
+	exitPermissionDenied = 8 // accès refusé
+	exitNotFound         = 9 // fichier introuvable
+)
+
+var errorExitCodes = map[string]int{
+	config.ErrorCodeParse:            exitParseError,
+	config.ErrorCodeTimeout:          exitTimeout,
+	config.ErrorCodeIO:               exitIOError,
+	config.ErrorCodeIsDirectory:      exitIsDirectory,
+	config.ErrorCodePermissionDenied: exitPermissionDenied,
+	config.ErrorCodeNotFound:         exitNotFound,
+	config.ErrorCodeInterrupted:      exitInterrupted,
+}
+
+// resultsExitCode renvoie le code de la pire erreur des résultats (exitOK si aucune).
+// Le code le plus grand l'emporte: 130 (interruption), puis 9 à 4. Il masque
+// exitAlerts, qui n'est renvoyé que si aucun fichier n'est en erreur.
+func resultsExitCode(results []config.AnalysisResult) int {
+	code := exitOK
+	for _, result := range results {
+		exit, known := errorExitCodes[result.ErrorCode]
+		if !known && result.Status != config.StatusOK {
+			exit = exitError // échec sans code connu: jamais 0
+		}
+		if exit > code {
+			code = exit
+		}
+	}
+	return code
+}
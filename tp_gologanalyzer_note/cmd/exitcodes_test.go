@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+func TestResultsExitCode(t *testing.T) {
+	ok := config.AnalysisResult{Status: config.StatusOK}
+	failed := func(code string) config.AnalysisResult {
+		return config.AnalysisResult{Status: config.StatusFailed, ErrorCode: code}
+	}
+	timedOut := config.AnalysisResult{Status: config.StatusTimeout, ErrorCode: config.ErrorCodeTimeout}
+
+	tests := []struct {
+		name    string
+		results []config.AnalysisResult
+		want    int
+	}{
+		{name: "aucun résultat", want: exitOK},
+		{name: "tout OK", results: []config.AnalysisResult{ok, ok}, want: exitOK},
+		{name: "erreur de parsing", results: []config.AnalysisResult{ok, failed(config.ErrorCodeParse)}, want: exitParseError},
+		{name: "timeout", results: []config.AnalysisResult{timedOut, ok}, want: exitTimeout},
+		{name: "lecture", results: []config.AnalysisResult{failed(config.ErrorCodeIO)}, want: exitIOError},
+		{name: "répertoire", results: []config.AnalysisResult{failed(config.ErrorCodeIsDirectory)}, want: exitIsDirectory},
+		{name: "accès refusé", results: []config.AnalysisResult{failed(config.ErrorCodePermissionDenied)}, want: exitPermissionDenied},
+		{
+			name:    "mélange: le plus grave l'emporte",
+			results: []config.AnalysisResult{failed(config.ErrorCodeParse), failed(config.ErrorCodeNotFound), timedOut, ok},
+			want:    exitNotFound,
+		},
+		{
+			name:    "mélange timeout et lecture",
+			results: []config.AnalysisResult{timedOut, failed(config.ErrorCodeIO), failed(config.ErrorCodeParse)},
+			want:    exitIOError,
+		},
+		{
+			name:    "interruption",
+			results: []config.AnalysisResult{failed(config.ErrorCodeNotFound), failed(config.ErrorCodeInterrupted), ok},
+			want:    exitInterrupted,
+		},
+		{name: "échec sans code connu", results: []config.AnalysisResult{ok, failed("")}, want: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultsExitCode(tt.results); got != tt.want {
+				t.Errorf("code %d, attendu %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Version de l'outil, surchargée au build:
+// go build -ldflags "-X github.com/axellelanca/go_loganizer/cmd.Version=1.2.0"
+var Version = "dev"
+
 var rootCmd = &cobra.Command{
-	Use:   "loganalyzer",
+	Use:     "loganalyzer",
+	Version: Version,
 	Short: "Outil d'analyse de logs",
 	Long: `loganalyzer est un outil CLI pour analyser des fichiers de logs de diverses sources (serveurs, applications).
 			Il analyse plusieurs logs en parallèle et extrait des infos utiles avec gestion d'erreurs robuste.`,
@@ -16,7 +21,7 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
 
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/analyzer"
+	"github.com/axellelanca/go_loganizer/internal/config"
+	"github.com/axellelanca/go_loganizer/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchContext    int
+	searchMaxCount   int
+	searchIgnoreCase bool
+	searchJSON       bool
+	searchWorkers    int
+	searchTimeout    time.Duration
+	searchFilter     config.Filter
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <regex>",
+	Short: "Cherche une expression régulière dans les logs de la config",
+	Long: `Cherche les lignes qui correspondent à l'expression (syntaxe Go/RE2) dans tous
+			les logs de la config, en parallèle comme analyze. Affiche l'ID du log,
+			le numéro de ligne et le contexte éventuel, ou du JSON avec --json.
+			Exemple:
+  			loganalyzer search -c config.json -C 2 'timeout|refused'`,
+	Args: cobra.ExactArgs(1),
+	Run:  executeSearch,
+}
+
+func executeSearch(cmd *cobra.Command, args []string) {
+	expression := args[0]
+	if searchIgnoreCase {
+		expression = "(?i)" + expression
+	}
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		fmt.Printf("Erreur: expression invalide: %v\n", err)
+		os.Exit(exitError)
+	}
+	if searchContext < 0 || searchMaxCount < 0 {
+		fmt.Println("Erreur: --context et --max-count doivent être positifs")
+		os.Exit(exitError)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Erreur config: %v\n", err)
+		os.Exit(exitError)
+	}
+	if err := searchFilter.Validate(); err != nil {
+		fmt.Printf("Erreur filtre: %v\n", err)
+		os.Exit(exitError)
+	}
+	logConfigs := searchFilter.Apply(cfg.Logs)
+	if len(logConfigs) == 0 {
+		fmt.Println("Erreur: aucun log ne correspond aux filtres")
+		os.Exit(exitError)
+	}
+
+	// Mêmes réglages globaux qu'analyze si les flags ne sont pas donnés
+	if !cmd.Flags().Changed("workers") {
+		searchWorkers = cfg.Settings.Workers
+	}
+	if !cmd.Flags().Changed("timeout") {
+		searchTimeout = time.Duration(cfg.Settings.Timeout)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	results := analyzer.SearchLogsConcurrently(ctx, logConfigs, analyzer.SearchOptions{
+		Pattern:    pattern,
+		Context:    searchContext,
+		MaxMatches: searchMaxCount,
+		Workers:    searchWorkers,
+		Timeout:    searchTimeout,
+	})
+	report := reporter.NewSearchReport(args[0], results)
+
+	if searchJSON {
+		if err := reporter.WriteSearchJSON(os.Stdout, report); err != nil {
+			fmt.Printf("Erreur: %v\n", err)
+			os.Exit(exitError)
+		}
+	} else {
+		reporter.PrintSearch(os.Stdout, report)
+	}
+
+	if ctx.Err() != nil {
+		stop()
+		os.Exit(exitInterrupted)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Fichier de config JSON, YAML ou TOML (obligatoire)")
+	searchCmd.Flags().IntVarP(&searchContext, "context", "C", 0,
+		"Lignes de contexte avant et après chaque correspondance")
+	searchCmd.Flags().IntVarP(&searchMaxCount, "max-count", "m", 0,
+		"Nombre max de correspondances par fichier (0 = pas de limite)")
+	searchCmd.Flags().BoolVarP(&searchIgnoreCase, "ignore-case", "i", false,
+		"Ignorer la casse")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false,
+		"Sortie JSON")
+	searchCmd.Flags().IntVarP(&searchWorkers, "workers", "w", 0,
+		"Nombre de fichiers lus en parallèle (0 = nombre de CPU)")
+	searchCmd.Flags().DurationVarP(&searchTimeout, "timeout", "t", 0,
+		"Durée max de recherche par fichier (0 = aucune)")
+	searchCmd.Flags().StringSliceVar(&searchFilter.IDs, "only", nil,
+		"Ne chercher que dans ces IDs (globs séparés par des virgules)")
+	searchCmd.Flags().StringSliceVar(&searchFilter.Tags, "tag", nil,
+		"Ne chercher que dans les logs ayant un de ces tags")
+	searchCmd.Flags().StringSliceVar(&searchFilter.Types, "type", nil,
+		"Ne chercher que dans les logs de ces types")
+
+	searchCmd.MarkFlagRequired("config")
+}
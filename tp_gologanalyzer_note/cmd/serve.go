@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/analyzer"
+	"github.com/axellelanca/go_loganizer/internal/config"
+	"github.com/axellelanca/go_loganizer/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen   string
+	serveInterval time.Duration
+	serveWorkers  int
+	serveTimeout  time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Analyse les logs à intervalle régulier et expose des métriques Prometheus",
+	Long: `Relance l'analyse de la config toutes les --interval et expose le résultat
+			du dernier run sur /metrics (format texte Prometheus), par ID et type de log.
+			La config est relue à chaque run (nouveaux fichiers des globs compris).
+			Exemple:
+  			loganalyzer serve -c config.json --listen :9100 --interval 1m`,
+	Run: executeServe,
+}
+
+func executeServe(cmd *cobra.Command, args []string) {
+	if serveInterval <= 0 {
+		fmt.Println("Erreur: --interval doit être positif")
+		os.Exit(exitError)
+	}
+
+	// Premier chargement: une config invalide arrête tout de suite
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Erreur config: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	// Port occupé => erreur immédiate plutôt que dans une goroutine
+	listener, err := net.Listen("tcp", serveListen)
+	if err != nil {
+		fmt.Printf("Erreur: impossible d'écouter sur %s: %v\n", serveListen, err)
+		os.Exit(exitError)
+	}
+
+	var mu sync.RWMutex
+	snapshot := &reporter.MetricsSnapshot{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		mu.RLock()
+		defer mu.RUnlock()
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		reporter.WriteMetrics(w, snapshot)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, "loganalyzer", Version, "- métriques sur /metrics")
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Erreur serveur: %v\n", err)
+		}
+	}()
+	fmt.Printf("Métriques sur http://%s/metrics (analyse toutes les %s)\n", listener.Addr(), serveInterval)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(serveInterval)
+	defer ticker.Stop()
+
+	runs, configErrors := 0, 0
+	for {
+		next, ok := runServeAnalysis(ctx, cmd, cfg)
+		if ok {
+			runs++
+			next.Runs, next.ConfigErrors = runs, configErrors
+			mu.Lock()
+			snapshot = next
+			mu.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nArrêt du serveur")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+			return
+		case <-ticker.C:
+		}
+
+		// La config est relue; en cas d'erreur on garde la précédente
+		reloaded, err := config.LoadConfig(configPath)
+		if err != nil {
+			configErrors++
+			fmt.Printf("Erreur config (config précédente conservée): %v\n", err)
+			mu.Lock()
+			snapshot.ConfigErrors = configErrors
+			mu.Unlock()
+			continue
+		}
+		cfg = reloaded
+	}
+}
+
+// runServeAnalysis lance un run; false s'il a été interrompu
+func runServeAnalysis(ctx context.Context, cmd *cobra.Command, cfg *config.Config) (*reporter.MetricsSnapshot, bool) {
+	// Flags prioritaires sur les réglages de la config
+	workers, timeout := cfg.Settings.Workers, time.Duration(cfg.Settings.Timeout)
+	if cmd.Flags().Changed("workers") {
+		workers = serveWorkers
+	}
+	if cmd.Flags().Changed("timeout") {
+		timeout = serveTimeout
+	}
+
+	startedAt := time.Now()
+	results := analyzer.AnalyzeLogsConcurrently(ctx, cfg.Logs, analyzer.Options{
+		Workers: workers,
+		Timeout: timeout,
+	})
+	finishedAt := time.Now()
+	if ctx.Err() != nil {
+		return nil, false
+	}
+	alerts := analyzer.EvaluateRules(cfg.Rules, results, finishedAt)
+
+	summary := reporter.Summarize(results)
+	fmt.Printf("[%s] %d fichiers - Succès: %d | Échecs: %d | Timeouts: %d | Alertes: %d (%s)\n",
+		finishedAt.Format("15:04:05"), summary.Total, summary.OK, summary.Failed, summary.Timeout,
+		len(alerts), finishedAt.Sub(startedAt).Round(time.Millisecond))
+
+	return &reporter.MetricsSnapshot{
+		Configs:    cfg.Logs,
+		Results:    results,
+		Alerts:     alerts,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+	}, true
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Fichier de config JSON, YAML ou TOML (obligatoire)")
+	serveCmd.Flags().StringVarP(&serveListen, "listen", "l", ":9100",
+		"Adresse d'écoute HTTP")
+	serveCmd.Flags().DurationVarP(&serveInterval, "interval", "i", time.Minute,
+		"Intervalle entre deux analyses")
+	serveCmd.Flags().IntVarP(&serveWorkers, "workers", "w", 0,
+		"Nombre de fichiers analysés en parallèle (0 = nombre de CPU)")
+	serveCmd.Flags().DurationVarP(&serveTimeout, "timeout", "t", 0,
+		"Durée max d'analyse par fichier (0 = aucune)")
+
+	serveCmd.MarkFlagRequired("config")
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/analyzer"
+	"github.com/axellelanca/go_loganizer/internal/config"
+	"github.com/axellelanca/go_loganizer/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval time.Duration
+	watchFromEnd  bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Suit les fichiers de logs en continu (tail -f)",
+	Long: `Garde les fichiers de la config ouverts, lit les lignes ajoutées et rafraîchit
+			les statistiques à intervalle régulier. Gère la rotation et la troncature;
+			un fichier absent est recherché à chaque rafraîchissement.
+			Exemple:
+  			loganalyzer watch -c config.json --interval 5s`,
+	Run: executeWatch,
+}
+
+func executeWatch(cmd *cobra.Command, args []string) {
+	if watchInterval <= 0 {
+		fmt.Println("Erreur: --interval doit être positif")
+		os.Exit(exitError)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Erreur config: %v\n", err)
+		os.Exit(exitError)
+	}
+	logConfigs := cfg.Logs
+
+	// Un type de log sans parser garde un résultat FAILED; un fichier absent
+	// est recherché à chaque rafraîchissement par son Follower
+	followers := make([]*analyzer.Follower, len(logConfigs))
+	failures := make(map[int]config.AnalysisResult)
+	for i, logConfig := range logConfigs {
+		follower, err := analyzer.NewFollower(logConfig, watchFromEnd)
+		if err != nil {
+			failures[i] = config.AnalysisResult{
+				LogID:        logConfig.ID,
+				SourceID:     logConfig.SourceID,
+				FilePath:     logConfig.Path,
+				Status:       config.StatusFailed,
+				Message:      "Suivi impossible",
+				ErrorDetails: err.Error(),
+				ErrorCode:    analyzer.ErrorCode(err),
+			}
+			continue
+		}
+		defer follower.Close()
+		followers[i] = follower
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		results := make([]config.AnalysisResult, len(logConfigs))
+		for i, follower := range followers {
+			if follower == nil {
+				results[i] = failures[i]
+				continue
+			}
+			// Une erreur rend le résultat FAILED jusqu'au prochain Poll réussi
+			follower.Poll()
+			results[i] = follower.Result()
+		}
+		alerts := analyzer.EvaluateRules(cfg.Rules, results, time.Now())
+
+		select {
+		case <-ctx.Done():
+			reporter.PrintResults(results, alerts)
+			return
+		default:
+		}
+
+		reporter.PrintWatch(results, alerts, time.Now())
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nArrêt du suivi")
+			reporter.PrintResults(results, alerts)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Fichier de config JSON, YAML ou TOML (obligatoire)")
+	watchCmd.Flags().DurationVarP(&watchInterval, "interval", "i", 5*time.Second,
+		"Intervalle de rafraîchissement")
+	watchCmd.Flags().BoolVar(&watchFromEnd, "from-end", false,
+		"Ignorer le contenu existant, ne compter que les nouvelles lignes")
+
+	watchCmd.MarkFlagRequired("config")
+}
@@ -2,7 +2,12 @@ module github.com/axellelanca/go_loganizer
 
 go 1.24.3
 
-require github.com/spf13/cobra v1.10.1
+require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/klauspost/compress v1.18.0
+	github.com/spf13/cobra v1.10.1
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
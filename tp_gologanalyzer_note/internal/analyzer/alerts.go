@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// EvaluateRules applique les règles d'alerte aux résultats d'un run.
+// Les fichiers en échec ou en timeout sont ignorés (pas de statistiques fiables).
+func EvaluateRules(rules []config.AlertRule, results []config.AnalysisResult, now time.Time) []config.Alert {
+	var alerts []config.Alert
+	for _, rule := range rules {
+		for _, result := range results {
+			if result.Status != config.StatusOK || !rule.Matches(result.LogID, result.SourceID) {
+				continue
+			}
+			value, ok := ruleMetric(rule.Parsed.Metric, result, now)
+			if !ok || !rule.Parsed.Compare(value) {
+				continue
+			}
+
+			formatted := formatMetric(rule.Parsed, value)
+			alerts = append(alerts, config.Alert{
+				Rule:      rule.Name,
+				LogID:     result.LogID,
+				Condition: rule.Condition,
+				Value:     formatted,
+				Message:   fmt.Sprintf("%s: %s = %s (%s)", rule.Name, rule.Parsed.Metric, formatted, rule.Condition),
+			})
+		}
+	}
+	return alerts
+}
+
+// ruleMetric calcule la valeur d'une métrique; false si elle n'a pas de sens
+// pour ce log (ex: 5xx sur un log applicatif)
+func ruleMetric(metric string, result config.AnalysisResult, now time.Time) (float64, bool) {
+	var levels config.LevelCounts
+	if result.Levels != nil {
+		levels = *result.Levels
+	}
+	access := result.Access
+
+	switch metric {
+	case "lines":
+		return float64(result.TotalLines), true
+	case "parsed":
+		return float64(result.ParsedLines), true
+	case "unparsed":
+		return float64(result.UnparsedLines), true
+	case "unparsed_ratio":
+		return ratio(result.UnparsedLines, result.TotalLines)
+	case "warnings":
+		return float64(levels.Warn), true
+	case "errors":
+		return float64(levels.Error + levels.Fatal), true
+	case "fatal":
+		return float64(levels.Fatal), true
+	case "error_ratio":
+		return ratio(levels.Error+levels.Fatal, result.ParsedLines)
+	case "requests":
+		if access == nil {
+			return 0, false
+		}
+		return float64(access.Requests), true
+	case "4xx", "5xx", "4xx_ratio", "5xx_ratio":
+		if access == nil {
+			return 0, false
+		}
+		count := access.StatusClasses.ServerError
+		if metric[0] == '4' {
+			count = access.StatusClasses.ClientError
+		}
+		if len(metric) > 3 {
+			return ratio(count, access.Requests)
+		}
+		return float64(count), true
+	case "silence":
+		// Fichier vide: aucune ligne depuis toujours
+		if result.TotalLines == 0 {
+			return math.Inf(1), true
+		}
+		if result.LastSeen == nil {
+			return 0, false
+		}
+		return now.Sub(*result.LastSeen).Seconds(), true
+	}
+	return 0, false
+}
+
+func ratio(count, total int) (float64, bool) {
+	if total == 0 {
+		return 0, false
+	}
+	return float64(count) / float64(total), true
+}
+
+// formatMetric affiche la valeur dans l'unité de la condition
+func formatMetric(condition config.Condition, value float64) string {
+	switch {
+	case condition.IsDuration():
+		if math.IsInf(value, 1) {
+			return "aucune ligne"
+		}
+		return time.Duration(value * float64(time.Second)).Round(time.Second).String()
+	case condition.IsRatio():
+		return strconv.FormatFloat(value*100, 'f', 2, 64) + "%"
+	default:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+}
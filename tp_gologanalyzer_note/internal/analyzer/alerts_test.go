@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// newRule construit une règle d'alerte dont la condition est analysée
+func newRule(t *testing.T, name, logs, condition string) config.AlertRule {
+	t.Helper()
+	parsed, err := config.ParseCondition(condition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.AlertRule{Name: name, Logs: logs, Condition: condition, Parsed: parsed}
+}
+
+func TestEvaluateRules(t *testing.T) {
+	now := time.Date(2025, 9, 24, 12, 0, 0, 0, time.UTC)
+	lastSeen := now.Add(-90 * time.Minute)
+
+	app := config.AnalysisResult{
+		LogID: "app", Status: config.StatusOK,
+		TotalLines: 100, ParsedLines: 80, UnparsedLines: 20,
+		Levels:   &config.LevelCounts{Info: 60, Warn: 5, Error: 9, Fatal: 1},
+		LastSeen: &lastSeen,
+	}
+	web := config.AnalysisResult{
+		LogID: "web-1", SourceID: "web", Status: config.StatusOK,
+		TotalLines: 200, ParsedLines: 200,
+		Levels: &config.LevelCounts{Info: 190, Warn: 8, Error: 2},
+		Access: &config.AccessStats{
+			Requests:      200,
+			StatusClasses: config.StatusClassCounts{ClientError: 8, ServerError: 2},
+		},
+		LastSeen: &now,
+	}
+	empty := config.AnalysisResult{LogID: "vide", Status: config.StatusOK}
+
+	tests := []struct {
+		name      string
+		condition string
+		logs      string
+		results   []config.AnalysisResult
+		want      map[string]string // log -> valeur affichée
+	}{
+		{name: "lignes", condition: "lines >= 100", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"app": "100", "web-1": "200"}},
+		{name: "seuil exclu", condition: "lines > 100", results: []config.AnalysisResult{app},
+			want: map[string]string{}},
+		{name: "seuil inclus", condition: "errors >= 10", results: []config.AnalysisResult{app},
+			want: map[string]string{"app": "10"}}, // error + fatal
+		{name: "égalité", condition: "fatal == 1", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"app": "1"}},
+		{name: "inférieur", condition: "warnings < 8", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"app": "5"}},
+		{name: "différent", condition: "parsed != 80", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"web-1": "200"}},
+		{name: "ratio de lignes illisibles", condition: "unparsed_ratio >= 20%", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"app": "20.00%"}},
+		{name: "ratio d'erreurs", condition: "error_ratio > 12%", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"app": "12.50%"}},
+		{name: "ratio sans lignes", condition: "error_ratio <= 1", results: []config.AnalysisResult{empty},
+			want: map[string]string{}},
+		{name: "5xx", condition: "5xx >= 2", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"web-1": "2"}}, // pas de statistiques HTTP pour app
+		{name: "4xx ratio", condition: "4xx_ratio >= 4%", results: []config.AnalysisResult{web},
+			want: map[string]string{"web-1": "4.00%"}},
+		{name: "requêtes", condition: "requests <= 200", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"web-1": "200"}},
+		{name: "silence", condition: "silence > 1h", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"app": "1h30m0s"}},
+		{name: "silence fichier vide", condition: "silence > 1h", results: []config.AnalysisResult{empty},
+			want: map[string]string{"vide": "aucune ligne"}},
+		{name: "glob sur l'ID d'origine", condition: "lines > 0", logs: "web", results: []config.AnalysisResult{app, web},
+			want: map[string]string{"web-1": "200"}},
+		{name: "échec ignoré", condition: "lines >= 0", results: []config.AnalysisResult{
+			{LogID: "absent", Status: config.StatusFailed, ErrorCode: config.ErrorCodeNotFound},
+			{LogID: "lent", Status: config.StatusTimeout, ErrorCode: config.ErrorCodeTimeout, TotalLines: 50},
+		}, want: map[string]string{}},
+		{name: "silence sur échec ignoré", condition: "silence > 1h", results: []config.AnalysisResult{
+			{LogID: "absent", Status: config.StatusFailed},
+			{LogID: "lent", Status: config.StatusTimeout},
+		}, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := EvaluateRules([]config.AlertRule{newRule(t, "r", tt.logs, tt.condition)}, tt.results, now)
+			got := make(map[string]string, len(alerts))
+			for _, alert := range alerts {
+				got[alert.LogID] = alert.Value
+				if alert.Rule != "r" || alert.Condition != tt.condition {
+					t.Errorf("alerte %+v, attendu règle r, condition %q", alert, tt.condition)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("alertes %v, attendu %v", got, tt.want)
+			}
+			for logID, value := range tt.want {
+				if got[logID] != value {
+					t.Errorf("%s = %q, attendu %q", logID, got[logID], value)
+				}
+			}
+		})
+	}
+}
@@ -1,107 +1,479 @@
 package analyzer
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"math/rand"
+	"io"
 	"os"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/axellelanca/go_loganizer/internal/config"
 )
 
-// AnalyzeLogsConcurrently lance l'analyse en parallèle
-func AnalyzeLogsConcurrently(logConfigs []config.LogConfig) []config.AnalysisResult {
+// Nombre max d'erreurs de parsing gardées dans le résultat
+const maxParseErrors = 5
+
+// Fréquence de vérification de l'annulation pendant la lecture
+const cancelCheckInterval = 256
+
+// Options règle l'exécution de l'analyse
+type Options struct {
+	// Nombre de fichiers analysés en même temps (0 = nombre de CPU)
+	Workers int
+	// Durée max par fichier si la config n'en donne pas (0 = aucune)
+	Timeout time.Duration
+	// Si non nil, reprise à partir des offsets sauvegardés (analyse incrémentale)
+	Checkpoints *CheckpointStore
+	// Seules les lignes datées dans la fenêtre sont comptées (--since/--until)
+	Window config.TimeWindow
+	// Regroupement par motif pour tous les fichiers (sinon selon LogConfig.Cluster)
+	Cluster bool
+	// Histogramme pour tous les fichiers: minute, hour ou day (sinon LogConfig.Timeline)
+	Timeline string
+	// Si non nil, les IDs de requête sont rassemblés entre fichiers (voir Correlator.Report)
+	Correlator *Correlator
+}
+
+// AnalyzeLogsConcurrently analyse les fichiers avec un pool de workers borné.
+// Les résultats sont dans le même ordre que logConfigs. Si ctx est annulé,
+// les fichiers pas encore terminés sont marqués comme interrompus.
+func AnalyzeLogsConcurrently(ctx context.Context, logConfigs []config.LogConfig, opts Options) []config.AnalysisResult {
+	// Chaque worker écrit à l'index de sa config, pas besoin de trier après
+	results := make([]config.AnalysisResult, len(logConfigs))
+	runPool(len(logConfigs), opts.Workers, func(i int) {
+		start := time.Now()
+		results[i] = analyzeWithTimeout(ctx, logConfigs[i], opts)
+		results[i].DurationMs = time.Since(start).Milliseconds()
+	})
+	return results
+}
+
+// runPool exécute task(0..count-1) avec au plus workers goroutines (0 = nombre de CPU)
+func runPool(count, workers int, task func(i int)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > count {
+		workers = count
+	}
+
+	jobs := make(chan int)
 	var wg sync.WaitGroup
-	results := make(chan config.AnalysisResult, len(logConfigs))
-	
-	// Une goroutine par fichier
-	for _, logConfig := range logConfigs {
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go func(cfg config.LogConfig) {
+		go func() {
 			defer wg.Done()
-			result := analyzeLogFile(cfg)
-			results <- result
-		}(logConfig)
+			for i := range jobs {
+				task(i)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// analyzeWithTimeout applique le timeout du fichier et rend la main dès que
+// ctx est terminé, même si une lecture reste bloquée
+func analyzeWithTimeout(ctx context.Context, logConfig config.LogConfig, opts Options) config.AnalysisResult {
+	timeout := fileTimeout(logConfig, opts.Timeout)
+	var result config.AnalysisResult
+	err := withTimeout(ctx, timeout, func(ctx context.Context) bool {
+		result = analyzeLogFile(ctx, logConfig, opts)
+		return result.Status == config.StatusOK
+	})
+	if err == nil {
+		return result
+	}
+
+	// result peut encore être modifié par l'analyse bloquée: nouveau résultat
+	failed := config.AnalysisResult{
+		LogID:    logConfig.ID,
+		SourceID: logConfig.SourceID,
+		FilePath: logConfig.Path,
+	}
+	if IsTimeout(err) {
+		failResult(&failed, fmt.Sprintf("Délai dépassé (%s)", timeout), err)
+	} else {
+		failResult(&failed, "Analyse interrompue", err)
+	}
+	return failed
+}
+
+// withTimeout lance task dans une goroutine avec le timeout du fichier et rend
+// la main dès que ctx est terminé, même si une lecture reste bloquée dans task.
+// Renvoie nil si task s'est terminée à temps, ou juste après l'échéance avec
+// succès (task renvoie true), sinon une *TimeoutError ou l'erreur de ctx. Dans
+// ce cas, task peut encore tourner: ses résultats ne doivent pas être lus.
+func withTimeout(ctx context.Context, timeout time.Duration, task func(ctx context.Context) bool) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	// Fermer le channel quand tout est fini
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	if ctx.Err() == nil {
+		done := make(chan bool, 1)
+		go func() {
+			done <- task(ctx)
+		}()
 
-	// Récupération des résultats
-	var allResults []config.AnalysisResult
-	for result := range results {
-		allResults = append(allResults, result)
+		select {
+		case ok := <-done:
+			// Un fichier terminé juste avant l'échéance garde son résultat
+			if ctx.Err() == nil || ok {
+				return nil
+			}
+		case <-ctx.Done():
+		}
 	}
 
-	return allResults
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return NewTimeoutError(timeout, ctx.Err())
+	}
+	return ctx.Err()
+}
+
+// failResult marque le résultat en échec (ou timeout) avec le code de l'erreur
+func failResult(result *config.AnalysisResult, message string, err error) {
+	result.Status = config.StatusFailed
+	if IsTimeout(err) {
+		result.Status = config.StatusTimeout
+	}
+	result.Message = message
+	result.ErrorDetails = err.Error()
+	result.ErrorCode = ErrorCode(err)
+}
+
+// timelineFor: la tranche de la config est prioritaire sur celle des options
+func timelineFor(logConfig config.LogConfig, opts Options) string {
+	if logConfig.Timeline != "" {
+		return logConfig.Timeline
+	}
+	return opts.Timeline
+}
+
+// fileTimeout: le délai de la config est prioritaire sur celui des options
+func fileTimeout(logConfig config.LogConfig, defaultTimeout time.Duration) time.Duration {
+	if logConfig.Timeout > 0 {
+		return time.Duration(logConfig.Timeout)
+	}
+	return defaultTimeout
 }
 
 // analyzeLogFile analyse un fichier
-func analyzeLogFile(logConfig config.LogConfig) config.AnalysisResult {
+func analyzeLogFile(ctx context.Context, logConfig config.LogConfig, opts Options) config.AnalysisResult {
 	result := config.AnalysisResult{
 		LogID:    logConfig.ID,
+		SourceID: logConfig.SourceID,
 		FilePath: logConfig.Path,
 	}
 
 	// Le fichier existe ?
 	fileInfo, err := os.Stat(logConfig.Path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			result.Status = config.StatusFailed
-			result.Message = "Fichier introuvable"
-			result.ErrorDetails = err.Error()
+		err = openError(logConfig.Path, err)
+		if IsFileNotFound(err) {
+			failResult(&result, "Fichier introuvable", err)
 			return result
 		}
 		// Autre problème (permissions, etc.)
-		result.Status = config.StatusFailed  
-		result.Message = "Impossible d'accéder au fichier"
-		result.ErrorDetails = err.Error()
+		failResult(&result, "Impossible d'accéder au fichier", err)
 		return result
 	}
 
 	// C'est un dossier ?
 	if fileInfo.IsDir() {
-		result.Status = config.StatusFailed
-		result.Message = "C'est un répertoire, pas un fichier"
-		result.ErrorDetails = "path is a directory"
+		failResult(&result, "C'est un répertoire, pas un fichier", NewIsDirectoryError(logConfig.Path))
 		return result
 	}
 
 	// Fichier vide ?
-	if fileInfo.Size() == 0 {
+	if fileInfo.Mode().IsRegular() && fileInfo.Size() == 0 {
+		// Le prochain contenu repartira de zéro
+		if opts.Checkpoints != nil {
+			opts.Checkpoints.reset(logConfig.ID)
+		}
 		result.Status = config.StatusOK
 		result.Message = "Fichier vide - analyse terminée"
 		result.ErrorDetails = ""
 		return result
 	}
 
-	// Simulation du temps d'analyse
-	simulateAnalysis()
+	// Lecture et parsing ligne par ligne
+	if err := parseLogFile(ctx, logConfig, fileInfo, opts, &result); err != nil {
+		message := "Erreur lecture fichier"
+		switch {
+		case IsPermissionDenied(err):
+			message = "Impossible d'accéder au fichier"
+		case IsParseError(err):
+			message = "Format illisible"
+		}
+		failResult(&result, message, err)
+		return result
+	}
 
-	// Tentative de lecture
-	_, readErr := os.ReadFile(logConfig.Path)
-	if readErr != nil {
-		result.Status = config.StatusFailed
-		result.Message = "Erreur lecture fichier"
-		result.ErrorDetails = readErr.Error()
+	// Aucune ligne reconnue: le type ne correspond pas au fichier
+	if result.TotalLines > 0 && result.UnparsedLines == result.TotalLines {
+		failResult(&result, fmt.Sprintf("Aucune ligne reconnue sur %d", result.TotalLines),
+			NewParseError(fmt.Sprintf("type %s", logConfig.Type), errFormat))
 		return result
 	}
 
 	// Toutt va bien
 	result.Status = config.StatusOK
-	result.Message = fmt.Sprintf("Analyse terminée avec succès - taille: %d bytes", fileInfo.Size())
+	result.Message = fmt.Sprintf("Analyse terminée - %d lignes (%d parsées, %d non reconnues), taille: %d bytes",
+		result.TotalLines, result.ParsedLines, result.UnparsedLines, fileInfo.Size())
+	if result.Compression != CompressionNone {
+		result.Message += fmt.Sprintf(" (%s, %d bytes décompressés)", result.Compression, result.UncompressedSize)
+	}
+	if !opts.Window.IsZero() {
+		result.Message += fmt.Sprintf(" - fenêtre: %d hors fenêtre, %d sans date", result.OutOfWindow, result.Undated)
+		if result.SkippedBytes > 0 {
+			result.Message += fmt.Sprintf(", %d bytes sautés", result.SkippedBytes)
+		}
+		if result.PartialCounts {
+			result.Message += " (log trié lu en partie: compteurs de lignes partiels)"
+		}
+	}
+	if result.Resumed {
+		result.Message = fmt.Sprintf("Analyse incrémentale - %d nouvelles lignes depuis l'octet %d, cumul: %d lignes (%d parsées, %d non reconnues)",
+			result.NewLines, result.ResumedFrom, result.TotalLines, result.ParsedLines, result.UnparsedLines)
+	}
 	result.ErrorDetails = ""
 	
 	return result
 }
 
-// simulateAnalysis simule le temps de traitement
-func simulateAnalysis() {
-	// Délai aléatoire entre 50 et 200ms
-	delay := time.Duration(50+rand.Intn(151)) * time.Millisecond
-	time.Sleep(delay)
-}
\ No newline at end of file
+// parseLogFile lit le fichier en streaming et compte les lignes
+func parseLogFile(ctx context.Context, logConfig config.LogConfig, fileInfo os.FileInfo,
+	opts Options, result *config.AnalysisResult) error {
+	file, err := os.Open(logConfig.Path)
+	if err != nil {
+		return openError(logConfig.Path, err)
+	}
+	defer file.Close()
+
+	// Fermer le fichier débloque une lecture en cours si ctx est annulé
+	stop := context.AfterFunc(ctx, func() { file.Close() })
+	defer stop()
+
+	parser, err := parserFor(logConfig)
+	if err != nil {
+		return NewParseError("config", err)
+	}
+
+	stream, err := openLogStream(file)
+	if err != nil {
+		return NewParseError("décompression", err)
+	}
+	defer stream.Close()
+	result.Compression = stream.Compression
+
+	lines := newLineProcessor(parser)
+	lines.window = opts.Window
+	if opts.Correlator != nil {
+		lines.correlator = opts.Correlator
+		lines.correlation = newCorrelationSet()
+		lines.logID = logConfig.ID
+	}
+	var source io.Reader = stream
+	var offset int64
+
+	// Reprise depuis le dernier checkpoint
+	var resume *Checkpoint
+	if opts.Checkpoints != nil {
+		resume = opts.Checkpoints.resumePoint(logConfig, fileInfo, opts.Cluster || logConfig.Cluster,
+			timelineFor(logConfig, opts))
+	}
+	if resume != nil {
+		resume.restore(lines, result)
+		result.Resumed = true
+		result.ResumedFrom = resume.Offset
+		if opts.Correlator != nil {
+			opts.Correlator.markResumed(logConfig.ID)
+		}
+
+		if stream.Compression != CompressionNone {
+			// Archive compressée inchangée: rien à relire
+			lines.stats.fill(result)
+			result.CompressedSize = resume.Offset
+			result.UncompressedSize = resume.UncompressedSize
+			opts.Checkpoints.set(logConfig.ID, resume)
+			return nil
+		}
+		if _, err := file.Seek(resume.Offset, io.SeekStart); err != nil {
+			return err
+		}
+		source = file
+		offset = resume.Offset
+	}
+	if opts.Cluster || logConfig.Cluster {
+		lines.stats.enableClusters()
+	} else {
+		lines.stats.Clusters = nil
+	}
+	if bucket := timelineFor(logConfig, opts); bucket != "" {
+		lines.stats.enableTimeline(bucket)
+	} else {
+		lines.stats.Timeline = nil
+	}
+
+	// Log trié: saut direct vers le début de la fenêtre
+	seekable := stream.Compression == CompressionNone && fileInfo.Mode().IsRegular()
+	if resume == nil && logConfig.Sorted && seekable && !opts.Window.Since.IsZero() {
+		start, err := seekWindowStart(file, fileInfo.Size(), parser, opts.Window.Since)
+		if err != nil {
+			return err
+		}
+		if start > 0 {
+			if _, err := file.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+			source = file
+			offset = start
+			lines.startOffset = start
+			result.SkippedBytes = start
+			result.PartialCounts = true
+		}
+	}
+
+	reader := bufio.NewReader(source)
+	var checkpoint *Checkpoint
+
+	for {
+		if lines.lineNumber%cancelCheckInterval == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		// Une dernière ligne sans \n peut encore grandir: le checkpoint s'arrête avant
+		if readErr == io.EOF && opts.Checkpoints != nil && stream.Compression == CompressionNone {
+			checkpoint = newCheckpoint(logConfig, fileInfo, offset, stream.Compression, lines, result)
+		}
+
+		if line != "" {
+			lines.process(line, result)
+			offset += int64(len(line))
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		// Log trié: plus rien à compter après la fin de la fenêtre
+		if logConfig.Sorted && lines.pastWindow {
+			result.PartialCounts = true
+			break
+		}
+	}
+
+	lines.stats.fill(result)
+	if lines.correlation != nil {
+		lines.correlator.merge(lines.correlation)
+	}
+	if stream.Compression == CompressionNone {
+		size := offset
+		if !opts.Window.IsZero() {
+			size = fileInfo.Size() // lecture partielle possible
+		}
+		result.CompressedSize = size
+		result.UncompressedSize = size
+	} else {
+		result.CompressedSize = stream.CompressedSize()
+		result.UncompressedSize = stream.UncompressedSize()
+	}
+	if resume != nil {
+		result.NewLines = result.TotalLines - resume.TotalLines
+	}
+
+	if opts.Checkpoints != nil {
+		if checkpoint == nil {
+			checkpoint = newCheckpoint(logConfig, fileInfo, fileInfo.Size(), stream.Compression, lines, result)
+			checkpoint.UncompressedSize = result.UncompressedSize
+		}
+		opts.Checkpoints.set(logConfig.ID, checkpoint)
+	}
+	return nil
+}
+
+// lineProcessor applique le parser ligne par ligne et tient les compteurs
+type lineProcessor struct {
+	parser     Parser
+	stats      *fileStats
+	lineNumber int
+
+	// Fenêtre de dates; pastWindow passe à true dès qu'une ligne la dépasse
+	window     config.TimeWindow
+	pastWindow bool
+
+	// Offset de départ après un saut: les numéros de ligne sont relatifs
+	startOffset int64
+
+	// IDs de requête du fichier, fusionnés dans correlator à la fin
+	correlator  *Correlator
+	correlation *correlationSet
+	logID       string
+}
+
+func newLineProcessor(parser Parser) *lineProcessor {
+	return &lineProcessor{parser: parser, stats: newFileStats()}
+}
+
+// process traite une ligne brute (avec ou sans fin de ligne)
+func (p *lineProcessor) process(line string, result *config.AnalysisResult) {
+	p.lineNumber++
+
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return
+	}
+	result.TotalLines++
+
+	entry, err := p.parser.Parse(line)
+	if err != nil {
+		result.UnparsedLines++
+		if len(result.ParseErrors) < maxParseErrors {
+			position := fmt.Sprintf("ligne %d", p.lineNumber)
+			if p.startOffset > 0 {
+				position = fmt.Sprintf("ligne +%d après l'octet %d", p.lineNumber, p.startOffset)
+			}
+			parseErr := NewParseError(position, err)
+			result.ParseErrors = append(result.ParseErrors, parseErr.Error())
+		}
+		return
+	}
+
+	if !p.window.IsZero() {
+		switch {
+		case entry.Timestamp.IsZero():
+			result.Undated++
+			return
+		case p.window.Before(entry.Timestamp):
+			result.OutOfWindow++
+			return
+		case p.window.After(entry.Timestamp):
+			result.OutOfWindow++
+			p.pastWindow = true
+			return
+		}
+	}
+	result.ParsedLines++
+	p.stats.add(entry)
+	if p.correlation != nil {
+		if id := p.correlator.extract(line, entry); id != "" {
+			p.correlation.add(p.logID, id, entry)
+		}
+	}
+}
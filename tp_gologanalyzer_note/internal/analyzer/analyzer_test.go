@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Délais inégaux: les premiers fichiers finissent après les suivants
+func TestRunPool(t *testing.T) {
+	delays := []time.Duration{30, 5, 20, 1, 15, 1, 25, 5, 10, 1}
+	tests := []struct {
+		name     string
+		workers  int
+		inFlight int // nombre max de tâches simultanées attendu
+	}{
+		{name: "un worker", workers: 1, inFlight: 1},
+		{name: "trois workers", workers: 3, inFlight: 3},
+		{name: "plus de workers que de fichiers", workers: 50, inFlight: len(delays)},
+		{name: "défaut", workers: 0, inFlight: min(runtime.NumCPU(), len(delays))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning atomic.Int32
+			var mu sync.Mutex
+			var done []int
+			results := make([]int, len(delays))
+
+			runPool(len(delays), tt.workers, func(i int) {
+				current := running.Add(1)
+				for {
+					seen := maxRunning.Load()
+					if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+						break
+					}
+				}
+				time.Sleep(delays[i] * time.Millisecond)
+				results[i] = i * 10
+				mu.Lock()
+				done = append(done, i)
+				mu.Unlock()
+				running.Add(-1)
+			})
+
+			if got := int(maxRunning.Load()); got != tt.inFlight {
+				t.Errorf("%d tâche(s) simultanée(s) au plus, attendu %d", got, tt.inFlight)
+			}
+			if len(done) != len(delays) {
+				t.Fatalf("%d tâche(s) exécutée(s), attendu %d", len(done), len(delays))
+			}
+			for i, result := range results {
+				if result != i*10 {
+					t.Errorf("résultat %d = %d, attendu %d", i, result, i*10)
+				}
+			}
+		})
+	}
+}
+
+func TestRunPoolEmpty(t *testing.T) {
+	runPool(0, 4, func(i int) {
+		t.Errorf("tâche %d exécutée sans fichier", i)
+	})
+}
+
+// Les résultats suivent l'ordre de la config même si les gros fichiers finissent en dernier
+func TestAnalyzeLogsConcurrentlyOrder(t *testing.T) {
+	dir := t.TempDir()
+	var logConfigs []config.LogConfig
+	for i := 0; i < 8; i++ {
+		content := lineA
+		if i%3 == 0 {
+			for j := 0; j < 2000; j++ {
+				content += lineB
+			}
+		}
+		path := filepath.Join(dir, fmt.Sprintf("app%d.log", i))
+		writeFile(t, path, content)
+		logConfigs = append(logConfigs, config.LogConfig{ID: fmt.Sprintf("app%d", i), Path: path, Type: config.TypeCustomApp})
+	}
+	logConfigs = append(logConfigs, config.LogConfig{ID: "absent", Path: filepath.Join(dir, "absent.log"), Type: config.TypeCustomApp})
+
+	results := AnalyzeLogsConcurrently(context.Background(), logConfigs, Options{Workers: 3})
+	if len(results) != len(logConfigs) {
+		t.Fatalf("%d résultats, attendu %d", len(results), len(logConfigs))
+	}
+	for i, result := range results {
+		if result.LogID != logConfigs[i].ID {
+			t.Errorf("résultat %d = %s, attendu %s", i, result.LogID, logConfigs[i].ID)
+		}
+	}
+	if results[0].TotalLines != 2001 || results[1].TotalLines != 1 {
+		t.Errorf("lignes %d et %d, attendu 2001 et 1", results[0].TotalLines, results[1].TotalLines)
+	}
+	if last := results[len(results)-1]; last.Status != config.StatusFailed {
+		t.Errorf("status %s pour un fichier absent, attendu %s", last.Status, config.StatusFailed)
+	}
+}
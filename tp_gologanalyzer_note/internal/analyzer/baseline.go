@@ -0,0 +1,256 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Tranche utilisée pour calculer la baseline
+const BaselineBucket = "hour"
+
+// Classes HTTP suivies dans la répartition des codes
+var baselineStatusClasses = []string{"2xx", "3xx", "4xx", "5xx"}
+
+// Baseline garde les statistiques de référence par ID de log
+type Baseline struct {
+	UpdatedAt time.Time               `json:"updated_at"`
+	Logs      map[string]*LogBaseline `json:"logs"`
+}
+
+// LogBaseline: statistiques horaires d'un log sur la période enregistrée
+type LogBaseline struct {
+	SavedAt time.Time `json:"saved_at"`
+	Hours   int       `json:"hours"`
+	Lines   int       `json:"lines"`
+
+	// Lignes par heure et part d'erreurs (ERROR + FATAL) par heure
+	Volume    Stat `json:"volume_per_hour"`
+	ErrorRate Stat `json:"error_ratio"`
+
+	// Access logs: nombre de requêtes et part de chaque classe de codes
+	Requests  int                `json:"requests,omitempty"`
+	StatusMix map[string]float64 `json:"status_mix,omitempty"`
+}
+
+// Stat: moyenne et écart-type
+type Stat struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+}
+
+// LoadBaseline lit un fichier de baseline
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire la baseline: %w", err)
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("baseline invalide: %w", err)
+	}
+	if baseline.Logs == nil {
+		baseline.Logs = make(map[string]*LogBaseline)
+	}
+	return &baseline, nil
+}
+
+// NewBaseline renvoie une baseline vide
+func NewBaseline() *Baseline {
+	return &Baseline{Logs: make(map[string]*LogBaseline)}
+}
+
+// Save réécrit le fichier de baseline
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erreur sérialisation baseline: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("erreur écriture baseline: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Record remplace la baseline des logs analysés avec un histogramme horaire.
+// Renvoie les IDs ignorés (échec, aucune ligne datée).
+func (b *Baseline) Record(results []config.AnalysisResult, now time.Time) []string {
+	var skipped []string
+	for _, result := range results {
+		timeline := result.Timeline
+		if result.Status != config.StatusOK || timeline == nil || len(timeline.Lines) == 0 ||
+			timeline.Bucket != BaselineBucket {
+			skipped = append(skipped, result.LogID)
+			continue
+		}
+
+		log := &LogBaseline{SavedAt: now, Hours: len(timeline.Lines), Lines: result.ParsedLines}
+		volumes := make([]float64, len(timeline.Lines))
+		var ratios []float64
+		errors := 0
+		for i, lines := range timeline.Lines {
+			volumes[i] = float64(lines)
+			errors += timeline.Errors[i]
+			if lines > 0 {
+				ratios = append(ratios, float64(timeline.Errors[i])/float64(lines))
+			}
+		}
+		log.Volume = newStat(volumes)
+		log.ErrorRate = newStat(ratios)
+		if lines := sum(timeline.Lines); lines > 0 {
+			log.ErrorRate.Mean = float64(errors) / float64(lines)
+		}
+
+		if access := result.Access; access != nil && access.Requests > 0 {
+			log.Requests = access.Requests
+			log.StatusMix = make(map[string]float64, len(baselineStatusClasses))
+			for _, class := range baselineStatusClasses {
+				log.StatusMix[class] = float64(statusClassCount(access.StatusClasses, class)) / float64(access.Requests)
+			}
+		}
+		b.Logs[result.LogID] = log
+	}
+	b.UpdatedAt = now
+	return skipped
+}
+
+// Detect ajoute aux résultats les métriques qui s'écartent de plus de sigma
+// écarts-types de la baseline. Renvoie les IDs sans baseline.
+func (b *Baseline) Detect(results []config.AnalysisResult, sigma float64) []string {
+	var missing []string
+	for i := range results {
+		result := &results[i]
+		log, ok := b.Logs[result.LogID]
+		if !ok {
+			missing = append(missing, result.LogID)
+			continue
+		}
+		if result.Status != config.StatusOK {
+			continue
+		}
+		result.Anomalies = log.anomalies(*result, sigma)
+	}
+	return missing
+}
+
+// anomalies compare un résultat à la baseline du log
+func (l *LogBaseline) anomalies(result config.AnalysisResult, sigma float64) []config.Anomaly {
+	var anomalies []config.Anomaly
+	check := func(metric string, value, expected, stddev float64) {
+		deviation := (value - expected) / stddev
+		if math.Abs(deviation) <= sigma {
+			return
+		}
+		anomalies = append(anomalies, config.Anomaly{
+			Metric:    metric,
+			Value:     value,
+			Expected:  expected,
+			StdDev:    stddev,
+			Deviation: deviation,
+			Message: fmt.Sprintf("%s = %s au lieu de %s ± %s (%+.1f σ)", metric,
+				formatStat(metric, value), formatStat(metric, expected), formatStat(metric, stddev), deviation),
+		})
+	}
+
+	// Volume: au moins le bruit d'un processus de Poisson (√moyenne)
+	if hours, ok := coveredHours(result); ok {
+		volume := float64(result.ParsedLines) / hours
+		check("volume_per_hour", volume, l.Volume.Mean, math.Max(l.Volume.StdDev, math.Max(math.Sqrt(l.Volume.Mean), 1)))
+	}
+
+	// Ratios: au moins l'erreur d'échantillonnage sur une heure moyenne
+	if result.ParsedLines > 0 && result.Levels != nil {
+		errors := result.Levels.Error + result.Levels.Fatal
+		ratio := float64(errors) / float64(result.ParsedLines)
+		stddev := math.Max(l.ErrorRate.StdDev, ratioNoise(l.ErrorRate.Mean, l.Volume.Mean))
+		check("error_ratio", ratio, l.ErrorRate.Mean, stddev)
+	}
+	if access := result.Access; access != nil && access.Requests > 0 && l.StatusMix != nil {
+		// Baseline écrite à la main sans "hours": une heure, pas de division par zéro
+		perHour := float64(l.Requests) / float64(max(l.Hours, 1))
+		for _, class := range baselineStatusClasses {
+			expected := l.StatusMix[class]
+			ratio := float64(statusClassCount(access.StatusClasses, class)) / float64(access.Requests)
+			check(class+"_ratio", ratio, expected, ratioNoise(expected, perHour))
+		}
+	}
+	return anomalies
+}
+
+// coveredHours: nombre d'heures entre la première et la dernière ligne datée;
+// un log sans ligne parsée couvre une heure (volume nul)
+func coveredHours(result config.AnalysisResult) (float64, bool) {
+	if result.ParsedLines == 0 {
+		return 1, true
+	}
+	if result.FirstSeen == nil || result.LastSeen == nil {
+		return 0, false
+	}
+	first := result.FirstSeen.Truncate(time.Hour)
+	last := result.LastSeen.Truncate(time.Hour)
+	return last.Sub(first).Hours() + 1, true
+}
+
+// ratioNoise: écart-type d'une proportion p sur n tirages, au moins 1/n
+func ratioNoise(p, n float64) float64 {
+	if n < 1 {
+		n = 1
+	}
+	return math.Max(math.Sqrt(p*(1-p)/n), 1/n)
+}
+
+func newStat(values []float64) Stat {
+	if len(values) == 0 {
+		return Stat{}
+	}
+	var stat Stat
+	for _, value := range values {
+		stat.Mean += value
+	}
+	stat.Mean /= float64(len(values))
+	for _, value := range values {
+		stat.StdDev += (value - stat.Mean) * (value - stat.Mean)
+	}
+	stat.StdDev = math.Sqrt(stat.StdDev / float64(len(values)))
+	return stat
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func statusClassCount(classes config.StatusClassCounts, class string) int {
+	switch class {
+	case "2xx":
+		return classes.Success
+	case "3xx":
+		return classes.Redirection
+	case "4xx":
+		return classes.ClientError
+	case "5xx":
+		return classes.ServerError
+	}
+	return 0
+}
+
+// formatStat: les ratios en pourcentage, le volume en lignes
+func formatStat(metric string, value float64) string {
+	if metric == "volume_per_hour" {
+		return fmt.Sprintf("%.1f", value)
+	}
+	return fmt.Sprintf("%.2f%%", value*100)
+}
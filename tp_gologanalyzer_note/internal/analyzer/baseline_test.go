@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Baseline parfaitement régulière: écart-type nul partout, le bruit minimal
+// (Poisson pour le volume, échantillonnage pour les ratios) sert d'échelle
+func TestAnomaliesZeroStdDev(t *testing.T) {
+	first := time.Date(2025, 9, 24, 10, 0, 0, 0, time.UTC)
+	last := first.Add(30 * time.Minute)
+	regular := &LogBaseline{
+		Hours: 24, Lines: 2400, Requests: 2400,
+		Volume:    Stat{Mean: 100},
+		ErrorRate: Stat{Mean: 0},
+		StatusMix: map[string]float64{"2xx": 1, "3xx": 0, "4xx": 0, "5xx": 0},
+	}
+	noHours := &LogBaseline{Requests: 100, StatusMix: map[string]float64{"2xx": 1}}
+
+	result := func(parsed, errors, success, serverErrors int) config.AnalysisResult {
+		result := config.AnalysisResult{
+			Status: config.StatusOK, ParsedLines: parsed,
+			Levels:    &config.LevelCounts{Info: parsed - errors, Error: errors},
+			FirstSeen: &first, LastSeen: &last,
+		}
+		if success+serverErrors > 0 {
+			result.Access = &config.AccessStats{
+				Requests:      success + serverErrors,
+				StatusClasses: config.StatusClassCounts{Success: success, ServerError: serverErrors},
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		baseline *LogBaseline
+		result   config.AnalysisResult
+		want     map[string]float64 // métrique -> écart attendu en σ
+	}{
+		{name: "identique", baseline: regular, result: result(100, 0, 100, 0), want: map[string]float64{}},
+		{name: "volume dans le bruit", baseline: regular, result: result(130, 0, 0, 0), want: map[string]float64{}},
+		{name: "volume hors du bruit", baseline: regular, result: result(140, 0, 0, 0),
+			want: map[string]float64{"volume_per_hour": 4}},
+		{name: "volume nul", baseline: regular, result: result(0, 0, 0, 0),
+			want: map[string]float64{"volume_per_hour": -10}},
+		{name: "erreurs sur baseline sans erreur", baseline: regular, result: result(100, 5, 0, 0),
+			want: map[string]float64{"error_ratio": 5}},
+		{name: "5xx sur baseline sans 5xx", baseline: regular, result: result(100, 0, 96, 4),
+			want: map[string]float64{"2xx_ratio": -4, "5xx_ratio": 4}}, // bruit: 1 / 100 requêtes par heure
+		{name: "baseline sans heures", baseline: noHours, result: result(0, 0, 10, 0),
+			want: map[string]float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomalies := tt.baseline.anomalies(tt.result, 3)
+			got := make(map[string]float64, len(anomalies))
+			for _, anomaly := range anomalies {
+				if math.IsNaN(anomaly.Deviation) || math.IsInf(anomaly.Deviation, 0) || !(anomaly.StdDev > 0) {
+					t.Errorf("anomalie %+v: écart non fini", anomaly)
+				}
+				got[anomaly.Metric] = math.Round(anomaly.Deviation*10) / 10
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("anomalies %v, attendu %v", got, tt.want)
+			}
+			for metric, deviation := range tt.want {
+				if got[metric] != deviation {
+					t.Errorf("%s: %+.1f σ, attendu %+.1f σ", metric, got[metric], deviation)
+				}
+			}
+		})
+	}
+}
+
+func TestBaselineRecordDetect(t *testing.T) {
+	now := time.Date(2025, 9, 24, 12, 0, 0, 0, time.UTC)
+	app := config.AnalysisResult{
+		LogID: "app", Status: config.StatusOK, ParsedLines: 30,
+		Timeline: &config.Timeline{Bucket: BaselineBucket, Lines: []int{10, 10, 10}, Errors: []int{1, 1, 1}},
+	}
+	baseline := NewBaseline()
+	skipped := baseline.Record([]config.AnalysisResult{
+		app,
+		{LogID: "absent", Status: config.StatusFailed},
+		{LogID: "minute", Status: config.StatusOK, Timeline: &config.Timeline{Bucket: "minute", Lines: []int{1}, Errors: []int{0}}},
+	}, now)
+	if strings.Join(skipped, ",") != "absent,minute" {
+		t.Errorf("ignorés %v, attendu absent, minute", skipped)
+	}
+	log := baseline.Logs["app"]
+	if log == nil || log.Hours != 3 || log.Volume != (Stat{Mean: 10}) ||
+		math.Abs(log.ErrorRate.Mean-0.1) > 1e-9 || log.ErrorRate.StdDev > 1e-9 {
+		t.Fatalf("baseline %+v, attendu 3 heures de 10 lignes à 10%% d'erreurs, écart-type nul", log)
+	}
+
+	first := now.Add(-time.Hour)
+	results := []config.AnalysisResult{
+		{LogID: "app", Status: config.StatusOK, ParsedLines: 50, FirstSeen: &first, LastSeen: &first,
+			Levels: &config.LevelCounts{Info: 50}},
+		{LogID: "inconnu", Status: config.StatusOK},
+		{LogID: "app", Status: config.StatusTimeout},
+	}
+	missing := baseline.Detect(results, 3)
+	if strings.Join(missing, ",") != "inconnu" {
+		t.Errorf("sans baseline %v, attendu inconnu", missing)
+	}
+	if len(results[0].Anomalies) != 1 || results[0].Anomalies[0].Metric != "volume_per_hour" {
+		t.Errorf("anomalies %+v, attendu le volume seulement", results[0].Anomalies)
+	}
+	if results[2].Anomalies != nil {
+		t.Errorf("anomalies %+v sur un timeout", results[2].Anomalies)
+	}
+}
@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Checkpoint mémorise jusqu'où un log a été analysé et les statistiques cumulées
+type Checkpoint struct {
+	Path        string    `json:"path"`
+	Inode       uint64    `json:"inode"`
+	Offset      int64     `json:"offset"`
+	Compression string    `json:"compression"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Taille décompressée des archives, pour ne pas les relire
+	UncompressedSize int64 `json:"uncompressed_size,omitempty"`
+
+	// Options qui ont produit les statistiques (motifs, tranche de l'histogramme)
+	Cluster  bool   `json:"cluster,omitempty"`
+	Timeline string `json:"timeline,omitempty"`
+
+	LineNumber    int        `json:"line_number"`
+	TotalLines    int        `json:"total_lines"`
+	ParsedLines   int        `json:"parsed_lines"`
+	UnparsedLines int        `json:"unparsed_lines"`
+	ParseErrors   []string   `json:"parse_errors,omitempty"`
+	Stats         *fileStats `json:"stats"`
+}
+
+// CheckpointStore est le fichier d'état partagé par les workers, clé = LogConfig.ID
+type CheckpointStore struct {
+	mu          sync.Mutex
+	path        string
+	checkpoints map[string]*Checkpoint
+}
+
+// LoadCheckpoints lit le fichier d'état; s'il n'existe pas l'état est vide
+func LoadCheckpoints(path string) (*CheckpointStore, error) {
+	store := &CheckpointStore{path: path, checkpoints: make(map[string]*Checkpoint)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire l'état: %w", err)
+	}
+	if len(data) == 0 {
+		return store, nil
+	}
+	if err := json.Unmarshal(data, &store.checkpoints); err != nil {
+		return nil, fmt.Errorf("fichier d'état invalide: %w", err)
+	}
+	return store, nil
+}
+
+// Save réécrit le fichier d'état
+func (s *CheckpointStore) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.checkpoints, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("erreur sérialisation état: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("erreur écriture état: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// resumePoint renvoie une copie du checkpoint si le fichier est toujours le même
+// et n'a pas rétréci; nil s'il faut repartir de zéro (rotation, troncature...).
+// Les motifs ou l'histogramme demandés mais absents du checkpoint ne couvriraient
+// que les nouvelles lignes: le fichier est alors relu en entier.
+func (s *CheckpointStore) resumePoint(logConfig config.LogConfig, info os.FileInfo, cluster bool, timeline string) *Checkpoint {
+	s.mu.Lock()
+	checkpoint, ok := s.checkpoints[logConfig.ID]
+	s.mu.Unlock()
+
+	if !ok || checkpoint.Stats == nil {
+		return nil
+	}
+	if checkpoint.Path != logConfig.Path || checkpoint.Inode != fileInode(info) {
+		return nil
+	}
+	if info.Size() < checkpoint.Offset {
+		return nil
+	}
+	if (cluster && !checkpoint.Cluster) || (timeline != "" && timeline != checkpoint.Timeline) {
+		return nil
+	}
+	// Un fichier compressé ne se reprend pas au milieu: inchangé ou relu
+	if checkpoint.Compression != CompressionNone && info.Size() != checkpoint.Offset {
+		return nil
+	}
+	return checkpoint.clone()
+}
+
+func (s *CheckpointStore) set(id string, checkpoint *Checkpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[id] = checkpoint
+}
+
+func (s *CheckpointStore) reset(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, id)
+}
+
+// clone copie le checkpoint pour le modifier sans toucher au store
+func (c *Checkpoint) clone() *Checkpoint {
+	copied := *c
+	copied.ParseErrors = append([]string(nil), c.ParseErrors...)
+	copied.Stats = c.Stats.clone()
+	if copied.Stats.ErrorMessages == nil {
+		copied.Stats.ErrorMessages = make(map[string]int)
+	}
+	return &copied
+}
+
+// restore remet les compteurs du checkpoint dans le résultat et le processor
+func (c *Checkpoint) restore(lines *lineProcessor, result *config.AnalysisResult) {
+	lines.stats = c.Stats
+	lines.lineNumber = c.LineNumber
+	result.TotalLines = c.TotalLines
+	result.ParsedLines = c.ParsedLines
+	result.UnparsedLines = c.UnparsedLines
+	result.ParseErrors = c.ParseErrors
+}
+
+// newCheckpoint photographie l'état courant (copie des statistiques)
+func newCheckpoint(logConfig config.LogConfig, info os.FileInfo, offset int64, compression string,
+	lines *lineProcessor, result *config.AnalysisResult) *Checkpoint {
+	var timeline string
+	if lines.stats.Timeline != nil {
+		timeline = lines.stats.TimelineBucket
+	}
+	return &Checkpoint{
+		Path:          logConfig.Path,
+		Inode:         fileInode(info),
+		Offset:        offset,
+		Compression:   compression,
+		UpdatedAt:     time.Now(),
+		LineNumber:    lines.lineNumber,
+		TotalLines:    result.TotalLines,
+		ParsedLines:   result.ParsedLines,
+		UnparsedLines: result.UnparsedLines,
+		ParseErrors:   append([]string(nil), result.ParseErrors...),
+		Stats:         lines.stats.clone(),
+		Cluster:       lines.stats.Clusters != nil,
+		Timeline:      timeline,
+	}
+}
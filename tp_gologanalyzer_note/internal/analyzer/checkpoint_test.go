@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Lignes custom-app datées utilisées par les tests
+const (
+	lineA = "2025-09-24 10:00:00 [INFO] démarrage\n"
+	lineB = "2025-09-24 10:00:01 [ERROR] connexion perdue\n"
+	lineC = "2025-09-24 10:00:02 [WARN] reprise lente\n"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func gzipData(t *testing.T, content string) string {
+	t.Helper()
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// analyzeWithState analyse un fichier avec le fichier d'état puis sauvegarde l'état,
+// comme deux lancements successifs de analyze --state
+func analyzeWithState(t *testing.T, statePath string, logConfig config.LogConfig, opts Options) config.AnalysisResult {
+	t.Helper()
+	store, err := LoadCheckpoints(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Checkpoints = store
+	result := analyzeLogFile(context.Background(), logConfig, opts)
+	if result.Status != config.StatusOK {
+		t.Fatalf("status %s: %s", result.Status, result.ErrorDetails)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestCheckpointResume(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial string
+		change  func(t *testing.T, path string)
+		opts    Options // options du second lancement
+
+		resumed     bool
+		resumedFrom int64
+		totalLines  int
+		newLines    int
+		errors      int
+	}{
+		{
+			name:        "ajout en fin de fichier",
+			initial:     lineA + lineB,
+			change:      func(t *testing.T, path string) { appendFile(t, path, lineC) },
+			resumed:     true,
+			resumedFrom: int64(len(lineA + lineB)),
+			totalLines:  3,
+			newLines:    1,
+			errors:      1,
+		},
+		{
+			name:    "dernière ligne incomplète",
+			initial: lineA + lineB[:20],
+			change: func(t *testing.T, path string) {
+				appendFile(t, path, lineB[20:]+lineC)
+			},
+			resumed:     true,
+			resumedFrom: int64(len(lineA)),
+			totalLines:  3,
+			newLines:    2,
+			errors:      1,
+		},
+		{
+			name:       "troncature",
+			initial:    lineA + lineB + lineC,
+			change:     func(t *testing.T, path string) { writeFile(t, path, lineB) },
+			totalLines: 1,
+			errors:     1,
+		},
+		{
+			name:    "rotation (nouvel inode)",
+			initial: lineA,
+			change: func(t *testing.T, path string) {
+				// Le nouveau fichier existe avant de remplacer l'ancien: inode différent
+				writeFile(t, path+".new", lineB+lineC)
+				if err := os.Rename(path+".new", path); err != nil {
+					t.Fatal(err)
+				}
+			},
+			totalLines: 2,
+			errors:     1,
+		},
+		{
+			name:        "archive compressée inchangée",
+			initial:     gzipData(t, lineA+lineB),
+			change:      func(t *testing.T, path string) {},
+			resumed:     true,
+			resumedFrom: int64(len(gzipData(t, lineA+lineB))),
+			totalLines:  2,
+			errors:      1,
+		},
+		{
+			name:       "regroupement activé après coup",
+			initial:    lineA + lineB,
+			change:     func(t *testing.T, path string) { appendFile(t, path, lineC) },
+			opts:       Options{Cluster: true},
+			totalLines: 3,
+			errors:     1,
+		},
+		{
+			name:       "histogramme activé après coup",
+			initial:    lineA + lineB,
+			change:     func(t *testing.T, path string) { appendFile(t, path, lineC) },
+			opts:       Options{Timeline: "minute"},
+			totalLines: 3,
+			errors:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			statePath := filepath.Join(dir, "state.json")
+			writeFile(t, path, tt.initial)
+			logConfig := config.LogConfig{ID: "app", Path: path, Type: "custom-app"}
+
+			analyzeWithState(t, statePath, logConfig, Options{})
+			tt.change(t, path)
+			result := analyzeWithState(t, statePath, logConfig, tt.opts)
+
+			if result.Resumed != tt.resumed {
+				t.Errorf("resumed = %v, attendu %v", result.Resumed, tt.resumed)
+			}
+			if result.ResumedFrom != tt.resumedFrom {
+				t.Errorf("resumed_from = %d, attendu %d", result.ResumedFrom, tt.resumedFrom)
+			}
+			if result.TotalLines != tt.totalLines {
+				t.Errorf("total_lines = %d, attendu %d", result.TotalLines, tt.totalLines)
+			}
+			if result.NewLines != tt.newLines {
+				t.Errorf("new_lines = %d, attendu %d", result.NewLines, tt.newLines)
+			}
+			if result.Levels == nil || result.Levels.Error != tt.errors {
+				t.Errorf("levels = %+v, attendu %d erreurs", result.Levels, tt.errors)
+			}
+
+			// Motifs et histogramme couvrent autant de lignes que les compteurs
+			if tt.opts.Cluster {
+				clustered := 0
+				for _, cluster := range result.Clusters {
+					clustered += cluster.Count
+				}
+				if clustered != result.ParsedLines {
+					t.Errorf("motifs sur %d lignes, %d lignes parsées", clustered, result.ParsedLines)
+				}
+			}
+			if tt.opts.Timeline != "" {
+				if result.Timeline == nil || sum(result.Timeline.Lines) != result.ParsedLines {
+					t.Errorf("histogramme %+v, %d lignes parsées", result.Timeline, result.ParsedLines)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"maps"
+	"regexp"
+	"sort"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Parties variables d'un message, remplacées dans l'ordre
+var templateMasks = []struct {
+	regex       *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<UUID>"},
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<IP>"},
+	// Apostrophe ouvrante seulement après un séparateur (pas "can't")
+	{regexp.MustCompile(`"[^"]*"|(^|[\s=:(\[])'[^']*'`), "${1}<STR>"},
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b|\b[0-9a-fA-F]{12,}\b`), "<HEX>"},
+	{regexp.MustCompile(`[-+]?\d+(?:\.\d+)?`), "<NUM>"},
+}
+
+// Template masque les parties variables d'un message (UUID, IP, chaînes entre
+// guillemets, hexa, nombres) pour regrouper les lignes de même forme
+func Template(message string) string {
+	for _, mask := range templateMasks {
+		message = mask.regex.ReplaceAllString(message, mask.placeholder)
+	}
+	return message
+}
+
+// clusterStats compte les lignes d'un motif et garde la première comme exemple
+type clusterStats struct {
+	Count  int    `json:"count"`
+	Sample string `json:"sample"`
+	Level  string `json:"level,omitempty"`
+}
+
+// enableClusters active le regroupement des messages par motif
+func (s *fileStats) enableClusters() {
+	if s.Clusters == nil {
+		s.Clusters = make(map[string]clusterStats)
+	}
+}
+
+// addCluster range le message dans son motif (pas plus de maxTrackedValues motifs)
+func (s *fileStats) addCluster(entry *LogEntry) {
+	template := Template(entry.Message)
+	cluster, exists := s.Clusters[template]
+	if !exists {
+		if len(s.Clusters) >= maxTrackedValues {
+			s.ClustersDropped++
+			return
+		}
+		cluster.Sample = entry.Message
+	}
+	cluster.Count++
+	// Le niveau le plus grave vu pour ce motif
+	if levelRank(NormalizeLevel(entry.Level)) > levelRank(cluster.Level) {
+		cluster.Level = NormalizeLevel(entry.Level)
+	}
+	s.Clusters[template] = cluster
+}
+
+// fillClusters copie les motifs les plus fréquents dans le résultat
+func (s *fileStats) fillClusters(result *config.AnalysisResult) {
+	if s.Clusters == nil {
+		return
+	}
+	clusters := make([]config.Cluster, 0, len(s.Clusters))
+	for template, stats := range s.Clusters {
+		clusters = append(clusters, config.Cluster{
+			Template: template,
+			Count:    stats.Count,
+			Level:    stats.Level,
+			Sample:   stats.Sample,
+		})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].Template < clusters[j].Template
+	})
+	if len(clusters) > DefaultTopN {
+		clusters = clusters[:DefaultTopN]
+	}
+	result.Clusters = clusters
+	result.DistinctTemplates = len(s.Clusters)
+}
+
+func cloneClusters(clusters map[string]clusterStats) map[string]clusterStats {
+	return maps.Clone(clusters)
+}
+
+func levelRank(level string) int {
+	switch level {
+	case LevelDebug:
+		return 1
+	case LevelInfo:
+		return 2
+	case LevelWarn:
+		return 3
+	case LevelError:
+		return 4
+	case LevelFatal:
+		return 5
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+func TestTemplate(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{message: "User 42 logged in after 3.5s", want: "User <NUM> logged in after <NUM>s"},
+		{message: "retry -1 / +2", want: "retry <NUM> / <NUM>"},
+		{message: "connexion de 192.168.1.10 refusée", want: "connexion de <IP> refusée"},
+		{message: "upstream 10.0.0.7:8080 timeout", want: "upstream <IP> timeout"},
+		{message: "version 1.2.3 chargée", want: "version <NUM>.<NUM> chargée"}, // pas une IP: décimal puis entier
+		{message: "req 550e8400-e29b-41d4-a716-446655440000 ok", want: "req <UUID> ok"},
+		{message: "addr 0xdeadBEEF, sha 3f786850e387550fdab836ed7e6dc881de23001b", want: "addr <HEX>, sha <HEX>"},
+		{message: `user "bob" not found`, want: "user <STR> not found"},
+		{message: "key='a b' (can't retry)", want: "key=<STR> (can't retry)"},
+		{message: "'quoted' at start", want: "<STR> at start"},
+		{message: "sans partie variable", want: "sans partie variable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := Template(tt.message); got != tt.want {
+				t.Errorf("Template(%q) = %q, attendu %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusters(t *testing.T) {
+	stats := newFileStats()
+	stats.enableClusters()
+	for _, entry := range []LogEntry{
+		{Level: "INFO", Message: "user 1 from 10.0.0.1"},
+		{Level: "ERROR", Message: "user 2 from 10.0.0.2"},
+		{Level: "WARN", Message: "user 3 from 10.0.0.3"},
+		{Level: "INFO", Message: "disk 90% full"},
+	} {
+		stats.addCluster(&entry)
+	}
+
+	var result config.AnalysisResult
+	stats.fillClusters(&result)
+	if result.DistinctTemplates != 2 || len(result.Clusters) != 2 {
+		t.Fatalf("%d motifs %+v, attendu 2", result.DistinctTemplates, result.Clusters)
+	}
+	want := config.Cluster{Template: "user <NUM> from <IP>", Count: 3, Level: "ERROR", Sample: "user 1 from 10.0.0.1"}
+	if result.Clusters[0] != want {
+		t.Errorf("motif %+v, attendu %+v", result.Clusters[0], want)
+	}
+	if result.Clusters[1].Template != "disk <NUM>% full" {
+		t.Errorf("motif %q, attendu disk <NUM>%% full", result.Clusters[1].Template)
+	}
+}
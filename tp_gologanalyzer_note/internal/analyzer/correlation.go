@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Nombre max d'IDs suivis (par fichier et au total)
+const maxCorrelatedIDs = 200000
+
+// Nombre de requêtes en erreur gardées dans le rapport
+const maxCorrelatedErrors = 20
+
+// Correlator rassemble les IDs de tous les fichiers analysés. Chaque fichier
+// remplit son propre correlationSet, fusionné à la fin de sa lecture.
+type Correlator struct {
+	field string
+	regex *regexp.Regexp
+
+	mu       sync.Mutex
+	requests map[string]*correlatedRequest
+	dropped  int
+	resumed  []string
+	closed   bool
+}
+
+// Ce qu'on garde d'un ID: bornes de temps, logs traversés,
+// première entrée access log et première erreur backend
+type correlatedRequest struct {
+	first, last time.Time
+	logs        []string
+	access      *config.CorrelatedEvent
+	err         *config.CorrelatedEvent
+	errors      int
+}
+
+// IDs vus dans un fichier
+type correlationSet struct {
+	requests map[string]*correlatedRequest
+	dropped  int
+}
+
+// NewCorrelator prépare la corrélation (la regex est compilée par la config)
+func NewCorrelator(correlation config.Correlation) *Correlator {
+	regex := correlation.Regex
+	if regex == nil {
+		regex = regexp.MustCompile(config.CorrelationPattern(correlation.Field))
+	}
+	return &Correlator{
+		field:    correlation.Field,
+		regex:    regex,
+		requests: make(map[string]*correlatedRequest),
+	}
+}
+
+// extract trouve l'ID: champ parsé (JSON...) sinon regex sur la ligne brute
+func (c *Correlator) extract(line string, entry *LogEntry) string {
+	if id := entry.Fields[c.field]; id != "" {
+		return id
+	}
+	if m := c.regex.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func newCorrelationSet() *correlationSet {
+	return &correlationSet{requests: make(map[string]*correlatedRequest)}
+}
+
+// add rattache une ligne parsée à son ID
+func (s *correlationSet) add(logID, id string, entry *LogEntry) {
+	request, ok := s.requests[id]
+	if !ok {
+		if len(s.requests) >= maxCorrelatedIDs {
+			s.dropped++
+			return
+		}
+		request = &correlatedRequest{logs: []string{logID}}
+		s.requests[id] = request
+	}
+
+	request.addTime(entry.Timestamp)
+	event := func() *config.CorrelatedEvent {
+		return &config.CorrelatedEvent{
+			LogID:     logID,
+			Timestamp: entry.Timestamp,
+			Level:     NormalizeLevel(entry.Level),
+			Message:   entry.Message,
+		}
+	}
+	if entry.HTTP != nil {
+		if request.access == nil {
+			request.access = event()
+		}
+		return
+	}
+	if level := NormalizeLevel(entry.Level); level == "ERROR" || level == "FATAL" {
+		request.errors++
+		if request.err == nil {
+			request.err = event()
+		}
+	}
+}
+
+func (r *correlatedRequest) addTime(ts time.Time) {
+	if ts.IsZero() {
+		return
+	}
+	if r.first.IsZero() || ts.Before(r.first) {
+		r.first = ts
+	}
+	if r.last.IsZero() || ts.After(r.last) {
+		r.last = ts
+	}
+}
+
+// merge ajoute les IDs d'un fichier; ignoré une fois le rapport produit
+func (c *Correlator) merge(set *correlationSet) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+
+	c.dropped += set.dropped
+	for id, other := range set.requests {
+		request, ok := c.requests[id]
+		if !ok {
+			if len(c.requests) >= maxCorrelatedIDs {
+				c.dropped++
+				continue
+			}
+			c.requests[id] = other
+			continue
+		}
+
+		request.logs = append(request.logs, other.logs...)
+		request.addTime(other.first)
+		request.addTime(other.last)
+		request.errors += other.errors
+		if request.access == nil {
+			request.access = other.access
+		}
+		if other.err != nil && (request.err == nil || other.err.Timestamp.Before(request.err.Timestamp)) {
+			request.err = other.err
+		}
+	}
+}
+
+// markResumed signale un log repris depuis un checkpoint: les IDs des runs
+// précédents ne sont pas conservés, seules les nouvelles lignes sont corrélées
+func (c *Correlator) markResumed(logID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.resumed = append(c.resumed, logID)
+	}
+}
+
+// Report clôt la corrélation et produit le rapport: les requêtes en erreur
+// côté backend, des plus lentes aux plus rapides
+func (c *Correlator) Report() *config.CorrelationReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+
+	report := &config.CorrelationReport{
+		Field:    c.field,
+		Requests: len(c.requests),
+		Dropped:  c.dropped,
+	}
+	if len(c.resumed) > 0 {
+		report.ResumedLogs = append([]string(nil), c.resumed...)
+		sort.Strings(report.ResumedLogs)
+	}
+	var errored []config.CorrelatedRequest
+	for id, request := range c.requests {
+		if len(request.logs) > 1 {
+			report.Joined++
+		}
+		if request.err == nil {
+			continue
+		}
+		report.Errored++
+		if request.access == nil {
+			report.Unmatched++
+		}
+
+		logs := append([]string(nil), request.logs...)
+		sort.Strings(logs)
+		errored = append(errored, config.CorrelatedRequest{
+			ID:        id,
+			Logs:      logs,
+			Start:     request.first,
+			End:       request.last,
+			LatencyMs: request.last.Sub(request.first).Milliseconds(),
+			Error:     *request.err,
+			Errors:    request.errors,
+			Access:    request.access,
+		})
+	}
+
+	sort.Slice(errored, func(i, j int) bool {
+		if errored[i].LatencyMs != errored[j].LatencyMs {
+			return errored[i].LatencyMs > errored[j].LatencyMs
+		}
+		return errored[i].ID < errored[j].ID
+	})
+	if len(errored) > maxCorrelatedErrors {
+		errored = errored[:maxCorrelatedErrors]
+	}
+	report.ErroredRequests = errored
+	return report
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+const (
+	accessR1 = `10.0.0.1 - - [24/Sep/2025:10:00:00 +0000] "POST /orders?rid=r1 HTTP/1.1" 500 12` + "\n"
+	accessR3 = `10.0.0.1 - - [24/Sep/2025:10:00:05 +0000] "GET /?rid=r3 HTTP/1.1" 200 12` + "\n"
+	appR1    = "2025-09-24T10:00:01.500+0000 ERROR db timeout rid=r1\n"
+	appR2    = "2025-09-24T10:00:02+0000 FATAL panic rid=r2\n"
+	appR3    = "2025-09-24T10:00:05+0000 INFO ok rid=r3\n"
+)
+
+// correlationLogs écrit un access log et un log applicatif partageant des IDs
+func correlationLogs(t *testing.T, access, app string) []config.LogConfig {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "access.log"), access)
+	writeFile(t, filepath.Join(dir, "app.log"), app)
+	return []config.LogConfig{
+		{ID: "nginx", Path: filepath.Join(dir, "access.log"), Type: config.TypeNginxAccess},
+		{ID: "api", Path: filepath.Join(dir, "app.log"), Type: config.TypeCustomApp},
+	}
+}
+
+func newTestCorrelator() *Correlator {
+	return NewCorrelator(config.Correlation{Field: "rid", Regex: regexp.MustCompile(`rid=(\w+)`)})
+}
+
+func TestCorrelatorJoinsFiles(t *testing.T) {
+	logConfigs := correlationLogs(t, accessR1+accessR3, appR1+appR2+appR3)
+	correlator := newTestCorrelator()
+	AnalyzeLogsConcurrently(context.Background(), logConfigs, Options{Workers: 2, Correlator: correlator})
+	report := correlator.Report()
+
+	if report.Requests != 3 || report.Joined != 2 || report.Errored != 2 || report.Unmatched != 1 {
+		t.Errorf("rapport %s, attendu 3 requêtes (2 dans plusieurs logs), 2 en erreur (1 sans access log)", report)
+	}
+	if len(report.ResumedLogs) != 0 {
+		t.Errorf("logs repris %v sans checkpoint", report.ResumedLogs)
+	}
+	if len(report.ErroredRequests) != 2 {
+		t.Fatalf("%d requêtes en erreur détaillées, attendu 2", len(report.ErroredRequests))
+	}
+
+	// La plus lente d'abord
+	r1 := report.ErroredRequests[0]
+	if r1.ID != "r1" || r1.LatencyMs != 1500 || strings.Join(r1.Logs, ",") != "api,nginx" ||
+		r1.Access == nil || r1.Error.Message != "db timeout rid=r1" {
+		t.Errorf("requête %+v, attendu r1 en 1500 ms avec son access log", r1)
+	}
+	if r2 := report.ErroredRequests[1]; r2.ID != "r2" || r2.Access != nil || r2.Error.Level != "FATAL" {
+		t.Errorf("requête %+v, attendu r2 FATAL sans access log", r2)
+	}
+}
+
+// Une chaîne commencée au run précédent est coupée: le rapport le signale
+func TestCorrelatorResumedLogs(t *testing.T) {
+	logConfigs := correlationLogs(t, accessR1, appR3)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	first := newTestCorrelator()
+	for _, logConfig := range logConfigs {
+		analyzeWithState(t, statePath, logConfig, Options{Correlator: first})
+	}
+	if report := first.Report(); report.Requests != 2 || len(report.ResumedLogs) != 0 {
+		t.Errorf("premier run: %s, repris %v, attendu 2 requêtes sans reprise", report, report.ResumedLogs)
+	}
+
+	appendFile(t, logConfigs[1].Path, appR1)
+	second := newTestCorrelator()
+	for _, logConfig := range logConfigs {
+		analyzeWithState(t, statePath, logConfig, Options{Correlator: second})
+	}
+	report := second.Report()
+	if strings.Join(report.ResumedLogs, ",") != "api,nginx" {
+		t.Errorf("logs repris %v, attendu api et nginx", report.ResumedLogs)
+	}
+	if report.Requests != 1 || report.Joined != 0 || report.Unmatched != 1 {
+		t.Errorf("second run: %s, attendu r1 sans son access log (lu au run précédent)", report)
+	}
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Formats de compression détectés
+const (
+	CompressionNone  = "none"
+	CompressionGzip  = "gzip"
+	CompressionBzip2 = "bzip2"
+	CompressionZstd  = "zstd"
+)
+
+// Signatures en début de fichier
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// logStream lit un log, décompressé à la volée si besoin
+type logStream struct {
+	io.Reader
+	Compression string
+
+	raw     *countingReader // octets lus sur le disque
+	plain   *countingReader // octets après décompression
+	closers []func()
+}
+
+// openLogStream détecte la compression par les magic bytes
+func openLogStream(source io.Reader) (*logStream, error) {
+	raw := &countingReader{reader: source}
+	buffered := bufio.NewReader(raw)
+	header, _ := buffered.Peek(len(zstdMagic))
+
+	stream := &logStream{raw: raw, Compression: CompressionNone}
+	var decoded io.Reader = buffered
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("gzip invalide: %w", err)
+		}
+		stream.Compression = CompressionGzip
+		stream.closers = append(stream.closers, func() { gz.Close() })
+		decoded = gz
+	case bytes.HasPrefix(header, bzip2Magic):
+		stream.Compression = CompressionBzip2
+		decoded = bzip2.NewReader(buffered)
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(buffered, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("zstd invalide: %w", err)
+		}
+		stream.Compression = CompressionZstd
+		stream.closers = append(stream.closers, zr.Close)
+		decoded = zr
+	}
+
+	if stream.Compression != CompressionNone {
+		decoded = &decodeErrorReader{reader: decoded, raw: raw, compression: stream.Compression}
+	}
+	stream.plain = &countingReader{reader: decoded}
+	stream.Reader = stream.plain
+	return stream, nil
+}
+
+// CompressedSize renvoie le nombre d'octets lus dans le fichier
+func (s *logStream) CompressedSize() int64 {
+	return s.raw.count
+}
+
+// UncompressedSize renvoie le nombre d'octets de texte produits
+func (s *logStream) UncompressedSize() int64 {
+	return s.plain.count
+}
+
+// Close libère les décodeurs (le fichier reste à fermer par l'appelant)
+func (s *logStream) Close() {
+	for _, closeFn := range s.closers {
+		closeFn()
+	}
+}
+
+// countingReader compte les octets lus et garde la dernière erreur de lecture
+type countingReader struct {
+	reader io.Reader
+	count  int64
+	err    error
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// decodeErrorReader type en *ParseError les erreurs du décodeur (archive corrompue
+// ou tronquée en cours de lecture), comme celles de l'en-tête. Les erreurs de
+// lecture du fichier, que le décodeur fait remonter telles quelles, restent des
+// erreurs d'E/S.
+type decodeErrorReader struct {
+	reader      io.Reader
+	raw         *countingReader
+	compression string
+}
+
+func (r *decodeErrorReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF && (r.raw.err == nil || !errors.Is(err, r.raw.err)) {
+		err = NewParseError("décompression", fmt.Errorf("%s invalide: %w", r.compression, err))
+	}
+	return n, err
+}
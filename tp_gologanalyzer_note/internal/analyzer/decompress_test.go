@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+	"github.com/klauspost/compress/zstd"
+)
+
+// failingReader renvoie data puis une erreur de lecture du disque
+type failingReader struct {
+	data *strings.Reader
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data.Len() == 0 {
+		return 0, r.err
+	}
+	return r.data.Read(p)
+}
+
+func zstdData(t *testing.T, content string) string {
+	t.Helper()
+	var b bytes.Buffer
+	zw, err := zstd.NewWriter(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// corrupt inverse les octets du milieu de data, en gardant l'en-tête
+func corrupt(data string) string {
+	b := []byte(data)
+	for i := len(b) / 2; i < len(b)-4; i++ {
+		b[i] ^= 0xff
+	}
+	return string(b)
+}
+
+func TestLogStreamReadErrors(t *testing.T) {
+	content := strings.Repeat(lineA+lineB+lineC, 200)
+	gz := gzipData(t, content)
+	diskErr := errors.New("erreur disque")
+
+	tests := []struct {
+		name   string
+		source io.Reader
+		code   string // error_code attendu en cours de lecture ("" = aucune erreur)
+	}{
+		{name: "gzip intact", source: strings.NewReader(gz)},
+		{name: "gzip corrompu", source: strings.NewReader(corrupt(gz)), code: config.ErrorCodeParse},
+		{name: "gzip tronqué", source: strings.NewReader(gz[:len(gz)/2]), code: config.ErrorCodeParse},
+		{name: "zstd corrompu", source: strings.NewReader(corrupt(zstdData(t, content))), code: config.ErrorCodeParse},
+		{
+			name:   "gzip, erreur disque",
+			source: &failingReader{data: strings.NewReader(gz[:len(gz)/2]), err: diskErr},
+			code:   config.ErrorCodeIO,
+		},
+		{
+			name:   "texte, erreur disque",
+			source: &failingReader{data: strings.NewReader(content), err: diskErr},
+			code:   config.ErrorCodeIO,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := openLogStream(tt.source)
+			if err != nil {
+				t.Fatalf("en-tête refusé: %v", err)
+			}
+			defer stream.Close()
+
+			_, err = io.ReadAll(stream)
+			if code := ErrorCode(err); code != tt.code {
+				t.Errorf("erreur %v, code %q, attendu %q", err, code, tt.code)
+			}
+			if tt.code == config.ErrorCodeIO && !errors.Is(err, diskErr) {
+				t.Errorf("erreur %v, attendu l'erreur du disque", err)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package analyzer
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
 )
 
 // Erreur fichier pas trouvé
@@ -32,6 +37,44 @@ func (e *ParseError) Error() string {
 func (e *ParseError) Unwrap() error {
 	return e.Err
 }
+
+// Erreur droits insuffisants
+type PermissionDeniedError struct {
+	Path string
+	Err  error
+}
+
+func (e *PermissionDeniedError) Error() string {
+	return fmt.Sprintf("accès refusé: %s", e.Path)
+}
+
+func (e *PermissionDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// Erreur chemin qui est un répertoire
+type IsDirectoryError struct {
+	Path string
+}
+
+func (e *IsDirectoryError) Error() string {
+	return fmt.Sprintf("%s est un répertoire", e.Path)
+}
+
+// Erreur délai dépassé
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("délai dépassé (%s)", e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
 // Constructeurs
 func NewFileNotFoundError(path string, err error) *FileNotFoundError {
 	return &FileNotFoundError{
@@ -47,6 +90,24 @@ func NewParseError(operation string, err error) *ParseError {
 	}
 }
 
+func NewPermissionDeniedError(path string, err error) *PermissionDeniedError {
+	return &PermissionDeniedError{
+		Path: path,
+		Err:  err,
+	}
+}
+
+func NewIsDirectoryError(path string) *IsDirectoryError {
+	return &IsDirectoryError{Path: path}
+}
+
+func NewTimeoutError(timeout time.Duration, err error) *TimeoutError {
+	return &TimeoutError{
+		Timeout: timeout,
+		Err:     err,
+	}
+}
+
 // Helpers pour vérifier le type d'erreur
 func IsFileNotFound(err error) bool {
 	var fileNotFoundErr *FileNotFoundError
@@ -56,4 +117,52 @@ func IsFileNotFound(err error) bool {
 func IsParseError(err error) bool {
 	var parseErr *ParseError
 	return errors.As(err, &parseErr)
-}
\ No newline at end of file
+}
+
+func IsPermissionDenied(err error) bool {
+	var permissionErr *PermissionDeniedError
+	return errors.As(err, &permissionErr)
+}
+
+func IsDirectory(err error) bool {
+	var directoryErr *IsDirectoryError
+	return errors.As(err, &directoryErr)
+}
+
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// ErrorCode donne le code stable (error_code) d'une erreur d'analyse
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case IsFileNotFound(err):
+		return config.ErrorCodeNotFound
+	case IsPermissionDenied(err):
+		return config.ErrorCodePermissionDenied
+	case IsDirectory(err):
+		return config.ErrorCodeIsDirectory
+	case IsTimeout(err):
+		return config.ErrorCodeTimeout
+	case IsParseError(err):
+		return config.ErrorCodeParse
+	case errors.Is(err, context.Canceled):
+		return config.ErrorCodeInterrupted
+	default:
+		return config.ErrorCodeIO
+	}
+}
+
+// openError type l'erreur d'ouverture ou de stat d'un fichier
+func openError(path string, err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return NewFileNotFoundError(path, err)
+	case errors.Is(err, fs.ErrPermission):
+		return NewPermissionDeniedError(path, err)
+	}
+	return err
+}
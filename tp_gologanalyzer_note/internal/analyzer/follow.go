@@ -0,0 +1,216 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Follower suit un fichier de log comme tail -f et cumule les statistiques.
+// La rotation (nouveau fichier au même chemin) et la troncature sont gérées.
+// Un fichier absent au démarrage est recherché à chaque Poll.
+type Follower struct {
+	config config.LogConfig
+	lines  *lineProcessor
+	result config.AnalysisResult
+
+	file    *os.File
+	info    os.FileInfo
+	reader  *bufio.Reader
+	offset  int64
+	partial string // début de ligne pas encore terminé par \n
+
+	// Nombre de rotations et troncatures vues
+	Rotations int
+
+	// Rotation vue mais nouveau fichier pas encore ouvert
+	reopening bool
+	// Erreur du dernier Poll (ou de l'ouverture), reprise dans Result
+	err error
+}
+
+// NewFollower ouvre le fichier; si fromEnd, seules les lignes ajoutées ensuite comptent.
+// Seul un type de log sans parser est une erreur: un fichier absent ou illisible
+// est rouvert au prochain Poll et, s'il apparaît, lu depuis le début.
+func NewFollower(logConfig config.LogConfig, fromEnd bool) (*Follower, error) {
+	parser, err := parserFor(logConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Follower{
+		config: logConfig,
+		lines:  newLineProcessor(parser),
+		result: config.AnalysisResult{
+			LogID:    logConfig.ID,
+			SourceID: logConfig.SourceID,
+			FilePath: logConfig.Path,
+		},
+	}
+	if logConfig.Cluster {
+		f.lines.stats.enableClusters()
+	}
+	if logConfig.Timeline != "" {
+		f.lines.stats.enableTimeline(logConfig.Timeline)
+	}
+	if err := f.open(); err != nil {
+		f.err = err
+		return f, nil
+	}
+
+	if fromEnd {
+		offset, err := f.file.Seek(0, io.SeekEnd)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		f.offset = offset
+		f.reader.Reset(f.file)
+	}
+	return f, nil
+}
+
+// open ouvre le fichier au chemin configuré et vérifie qu'il est lisible en continu
+func (f *Follower) open() error {
+	file, err := os.Open(f.config.Path)
+	if err != nil {
+		return openError(f.config.Path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if info.IsDir() {
+		file.Close()
+		return NewIsDirectoryError(f.config.Path)
+	}
+
+	// Une archive compressée ne grossit pas, on ne la suit pas
+	stream, err := openLogStream(file)
+	if err == nil {
+		stream.Close()
+	}
+	if err != nil || stream.Compression != CompressionNone {
+		file.Close()
+		return fmt.Errorf("fichier compressé, suivi impossible")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.info = info
+	f.reader = bufio.NewReader(file)
+	f.offset = 0
+	f.partial = ""
+	return nil
+}
+
+// Poll lit les lignes ajoutées depuis le dernier appel. L'erreur éventuelle est
+// gardée pour Result jusqu'au prochain appel réussi.
+func (f *Follower) Poll() error {
+	f.err = f.poll()
+	return f.err
+}
+
+func (f *Follower) poll() error {
+	// Fichier absent au démarrage: nouvel essai d'ouverture
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+
+	current, statErr := os.Stat(f.config.Path)
+
+	// Troncature: le fichier est plus petit que ce qu'on a lu
+	if statErr == nil && os.SameFile(current, f.info) && current.Size() < f.offset {
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		f.reader.Reset(f.file)
+		f.offset = 0
+		f.partial = ""
+		f.Rotations++
+	}
+
+	if err := f.readAvailable(); err != nil {
+		return err
+	}
+
+	// Rotation: un autre fichier a pris la place, l'ancien est lu jusqu'au bout.
+	// L'ancien n'est fermé qu'une fois le nouveau ouvert: en cas d'échec on
+	// continue de le suivre et la réouverture est retentée au prochain appel.
+	if statErr == nil && !os.SameFile(current, f.info) {
+		old, partial := f.file, f.partial
+		if err := f.open(); err != nil {
+			f.reopening = true
+			return fmt.Errorf("réouverture après rotation: %w", err)
+		}
+		f.reopening = false
+		if partial != "" {
+			f.lines.process(partial, &f.result)
+		}
+		old.Close()
+		f.Rotations++
+		return f.readAvailable()
+	}
+	return nil
+}
+
+// readAvailable lit jusqu'à la fin actuelle du fichier
+func (f *Follower) readAvailable() error {
+	for {
+		chunk, err := f.reader.ReadString('\n')
+		f.offset += int64(len(chunk))
+
+		if err == io.EOF {
+			// Ligne incomplète: on attend la suite
+			f.partial += chunk
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		f.lines.process(f.partial+chunk, &f.result)
+		f.partial = ""
+	}
+}
+
+// Result renvoie les statistiques cumulées à cet instant
+func (f *Follower) Result() config.AnalysisResult {
+	result := f.result
+	result.ParseErrors = append([]string(nil), f.result.ParseErrors...)
+	f.lines.stats.fill(&result)
+
+	result.Status = config.StatusOK
+	state := "Suivi en cours"
+	if f.err != nil {
+		result.Status = config.StatusFailed
+		result.ErrorDetails = f.err.Error()
+		result.ErrorCode = ErrorCode(f.err)
+		switch {
+		case f.file == nil:
+			state = "Fichier pas encore ouvert, nouvel essai au prochain rafraîchissement"
+		case f.reopening:
+			state = "Rotation détectée, réouverture du fichier au prochain rafraîchissement"
+		default:
+			state = "Lecture en échec, nouvel essai au prochain rafraîchissement"
+		}
+	}
+	result.Message = fmt.Sprintf("%s - %d lignes (%d parsées, %d non reconnues), %d rotations/troncatures",
+		state, result.TotalLines, result.ParsedLines, result.UnparsedLines, f.Rotations)
+	return result
+}
+
+// Close ferme le fichier suivi
+func (f *Follower) Close() {
+	if f.file != nil {
+		f.file.Close()
+	}
+}
@@ -0,0 +1,167 @@
+//go:build unix
+
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+func newFollower(t *testing.T, path string, fromEnd bool) *Follower {
+	t.Helper()
+	follower, err := NewFollower(config.LogConfig{ID: "app", Path: path, Type: config.TypeCustomApp}, fromEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(follower.Close)
+	return follower
+}
+
+// poll lit les ajouts et vérifie le résultat cumulé
+func poll(t *testing.T, follower *Follower, lines, rotations int) config.AnalysisResult {
+	t.Helper()
+	if err := follower.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	result := follower.Result()
+	if result.Status != config.StatusOK || result.TotalLines != lines || follower.Rotations != rotations {
+		t.Errorf("%s: %d lignes, %d rotation(s), attendu OK, %d lignes, %d rotation(s)",
+			result.Status, result.TotalLines, follower.Rotations, lines, rotations)
+	}
+	return result
+}
+
+func TestFollowerAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeFile(t, path, lineA)
+
+	follower := newFollower(t, path, false)
+	poll(t, follower, 1, 0)
+
+	// Ligne incomplète: comptée seulement une fois terminée
+	appendFile(t, path, lineB[:10])
+	poll(t, follower, 1, 0)
+	appendFile(t, path, lineB[10:])
+	result := poll(t, follower, 2, 0)
+	if result.Levels.Error != 1 {
+		t.Errorf("%d erreur(s), attendu 1 (ligne recollée)", result.Levels.Error)
+	}
+}
+
+func TestFollowerFromEnd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeFile(t, path, lineA+lineB)
+
+	follower := newFollower(t, path, true)
+	poll(t, follower, 0, 0)
+	appendFile(t, path, lineC)
+	poll(t, follower, 1, 0)
+}
+
+func TestFollowerTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeFile(t, path, lineA+lineB)
+
+	follower := newFollower(t, path, false)
+	poll(t, follower, 2, 0)
+
+	// Même fichier, plus petit que l'offset lu: relu depuis le début
+	writeFile(t, path, lineC)
+	poll(t, follower, 3, 1)
+	appendFile(t, path, lineA)
+	poll(t, follower, 4, 1)
+}
+
+func TestFollowerRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, lineA)
+
+	follower := newFollower(t, path, false)
+	poll(t, follower, 1, 0)
+
+	// L'ancien fichier reçoit encore une ligne après son renommage, sans \n final
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path+".1", strings.TrimSuffix(lineB, "\n"))
+	writeFile(t, path, lineC)
+
+	result := poll(t, follower, 3, 1)
+	if result.Levels.Error != 1 || result.Levels.Warn != 1 {
+		t.Errorf("niveaux %+v, attendu la fin de l'ancien fichier et le nouveau", *result.Levels)
+	}
+	appendFile(t, path+".1", lineA) // plus suivi
+	appendFile(t, path, lineA)
+	poll(t, follower, 4, 1)
+}
+
+// Rotation dont le nouveau fichier ne s'ouvre pas: l'erreur est affichée puis
+// la réouverture est retentée
+func TestFollowerRotationReopenError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, lineA)
+
+	follower := newFollower(t, path, false)
+	poll(t, follower, 1, 0)
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.Poll(); err == nil {
+		t.Fatal("aucune erreur, attendu un répertoire à la place du fichier")
+	}
+	result := follower.Result()
+	if result.Status != config.StatusFailed || result.ErrorCode != config.ErrorCodeIsDirectory ||
+		!strings.HasPrefix(result.Message, "Rotation détectée") {
+		t.Errorf("%s [%s] %q, attendu FAILED [%s] rotation détectée",
+			result.Status, result.ErrorCode, result.Message, config.ErrorCodeIsDirectory)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, lineB)
+	result = poll(t, follower, 2, 1)
+	if !strings.HasPrefix(result.Message, "Suivi en cours") || result.ErrorDetails != "" {
+		t.Errorf("message %q, erreur %q après réouverture", result.Message, result.ErrorDetails)
+	}
+}
+
+func TestFollowerMissingAtStartup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	// Même avec fromEnd, un fichier créé après le démarrage est lu en entier
+	follower := newFollower(t, path, true)
+	for i := 0; i < 2; i++ {
+		result := follower.Result()
+		if result.Status != config.StatusFailed || result.ErrorCode != config.ErrorCodeNotFound ||
+			!strings.HasPrefix(result.Message, "Fichier pas encore ouvert") {
+			t.Errorf("%s [%s] %q, attendu FAILED [%s] en attente du fichier",
+				result.Status, result.ErrorCode, result.Message, config.ErrorCodeNotFound)
+		}
+		if err := follower.Poll(); !IsFileNotFound(err) {
+			t.Errorf("erreur %v, attendu fichier introuvable", err)
+		}
+	}
+
+	writeFile(t, path, lineA+lineB)
+	poll(t, follower, 2, 0)
+	appendFile(t, path, lineC)
+	poll(t, follower, 3, 0)
+}
+
+func TestNewFollowerUnknownType(t *testing.T) {
+	_, err := NewFollower(config.LogConfig{ID: "app", Path: "app.log", Type: "inconnu"}, false)
+	if err == nil {
+		t.Error("aucune erreur pour un type sans parser")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package analyzer
+
+import "os"
+
+// fileInode: pas d'inode hors unix, seule la taille sert à détecter une rotation
+func fileInode(info os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package analyzer
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileInode renvoie l'inode du fichier, 0 si inconnu
+func fileInode(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Ino)
+	}
+	return 0
+}
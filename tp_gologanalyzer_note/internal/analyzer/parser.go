@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// LogEntry est une ligne de log reconnue par un parser
+type LogEntry struct {
+	Timestamp time.Time
+	Level     string
+	Message   string
+
+	// Champs supplémentaires (JSON, syslog...)
+	Fields map[string]string
+
+	// Renseigné pour les access logs HTTP
+	HTTP *HTTPRequest
+}
+
+// HTTPRequest décrit une requête d'un access log
+type HTTPRequest struct {
+	Client string
+	Method string
+	Path   string
+	Status int
+	Bytes  int64
+}
+
+// Parser transforme une ligne brute en LogEntry
+type Parser interface {
+	Parse(line string) (*LogEntry, error)
+}
+
+// ParserFunc permet d'utiliser une simple fonction comme Parser
+type ParserFunc func(line string) (*LogEntry, error)
+
+func (f ParserFunc) Parse(line string) (*LogEntry, error) {
+	return f(line)
+}
+
+var errFormat = errors.New("format non reconnu")
+
+// Registre des parsers, clé = LogConfig.Type
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Parser)
+)
+
+// Register associe un parser à un type de log.
+// Le type devient aussi valide pour config.LoadConfig.
+func Register(logType string, parser Parser) {
+	if logType == "" || parser == nil {
+		panic("analyzer: Register avec un type ou un parser vide")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[logType]; exists {
+		panic(fmt.Sprintf("analyzer: parser déjà enregistré pour %q", logType))
+	}
+	registry[logType] = parser
+	config.RegisterType(logType)
+}
+
+// LookupParser renvoie le parser enregistré pour un type
+func LookupParser(logType string) (Parser, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	parser, ok := registry[logType]
+	return parser, ok
+}
+
+// ParserTypes liste les types enregistrés, triés
+func ParserTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for logType := range registry {
+		types = append(types, logType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Formats de date rencontrés dans les logs
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02 15:04:05.999999999-0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+// Fuseau des dates sans fuseau, le même que pour --since/--until sans fuseau
+var logLocation = time.Local
+
+// parseTimestamp essaie les formats connus
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if ts, err := time.ParseInLocation(layout, value, logLocation); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date invalide %q", value)
+}
@@ -0,0 +1,304 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Parsers des types fournis avec l'outil (déclarés dans config)
+func init() {
+	access := ParserFunc(parseAccessLog)
+	Register(config.TypeNginxAccess, access)
+	Register(config.TypeNginxCombined, access)
+	Register(config.TypeApacheCommon, access)
+	Register(config.TypeMySQLError, ParserFunc(parseMySQLError))
+	Register(config.TypeSyslog, ParserFunc(parseSyslog))
+	Register(config.TypeJSONLines, ParserFunc(parseJSONLine))
+	Register(config.TypeCustomApp, ParserFunc(parseCustomApp))
+	Register(config.TypeGeneric, ParserFunc(parseGeneric))
+}
+
+// Format commun nginx/apache, avec referer et user-agent en option (combined)
+// Ex: 192.168.1.1 - - [10/Oct/2023:14:00:00 +0000] "GET /index.html HTTP/1.1" 200 1234
+var accessLogRegex = regexp.MustCompile(
+	`^(\S+) (?:\S+ ){1,2}\[([^\]]+)\] "(\S+) (\S+)(?: (\S+))?" (\d{3}) (\d+|-)(?: "([^"]*)" "([^"]*)")?`)
+
+// Ex: 2023-10-10T14:00:00.123456Z 0 [ERROR] [MY-010123] [Server] message
+// ou: 2023-10-10 14:00:00 1234 [Warning] message
+var mysqlErrorRegex = regexp.MustCompile(
+	`^(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})?)\s+(?:\d+\s+)?\[(\w+)\]\s*(.*)$`)
+
+// Ex: <34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed
+var syslog3164Regex = regexp.MustCompile(
+	`^(?:<(\d{1,3})>)?([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}) (\S+) ([^:\[\s]+)(?:\[(\d+)\])?: ?(.*)$`)
+
+// Ex: <165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [exampleSDID@32473 iut="3"] message
+var syslog5424Regex = regexp.MustCompile(
+	`^<(\d{1,3})>1 (\S+) (\S+) (\S+) (\S+) (\S+) (-|(?:\[[^\]]*\])+)(?: (.*))?$`)
+
+// Ex: ERROR: Failed to connect to database.
+// ou: 2023-10-10 14:00:00 [WARN] User session expired.
+var customAppRegex = regexp.MustCompile(
+	`^(?:(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?)\s+)?\[?(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|FATAL|CRITICAL)\]?:?\s+(.*)$`)
+
+// Niveau détecté n'importe où dans une ligne générique
+var genericLevelRegex = regexp.MustCompile(`\b(DEBUG|INFO|WARN|WARNING|ERROR|FATAL|CRITICAL)\b`)
+
+// Sévérités syslog (RFC 5424) 0..7
+var syslogSeverities = []string{"FATAL", "FATAL", "FATAL", "ERROR", "WARN", "INFO", "INFO", "DEBUG"}
+
+// Clés JSON usuelles
+var (
+	jsonTimeKeys    = []string{"time", "timestamp", "ts", "@timestamp"}
+	jsonLevelKeys   = []string{"level", "severity", "lvl"}
+	jsonMessageKeys = []string{"msg", "message"}
+)
+
+// parseAccessLog gère nginx access/combined et apache common
+func parseAccessLog(line string) (*LogEntry, error) {
+	m := accessLogRegex.FindStringSubmatch(line)
+	if m == nil {
+		return nil, errFormat
+	}
+	ts, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[2])
+	if err != nil {
+		return nil, fmt.Errorf("date invalide %q", m[2])
+	}
+
+	status, _ := strconv.Atoi(m[6])
+	var bytes int64
+	if m[7] != "-" {
+		bytes, _ = strconv.ParseInt(m[7], 10, 64)
+	}
+
+	entry := &LogEntry{
+		Timestamp: ts,
+		Level:     httpStatusLevel(status),
+		Message:   fmt.Sprintf("%s %s %s", m[3], m[4], m[6]),
+		HTTP: &HTTPRequest{
+			Client: m[1],
+			Method: m[3],
+			Path:   m[4],
+			Status: status,
+			Bytes:  bytes,
+		},
+	}
+	if m[8] != "" || m[9] != "" {
+		entry.Fields = map[string]string{
+			"referer":    m[8],
+			"user_agent": m[9],
+		}
+	}
+	return entry, nil
+}
+
+// httpStatusLevel: 5xx compte comme erreur, 4xx comme warning
+func httpStatusLevel(status int) string {
+	switch {
+	case status >= 500:
+		return "ERROR"
+	case status >= 400:
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
+
+func parseMySQLError(line string) (*LogEntry, error) {
+	m := mysqlErrorRegex.FindStringSubmatch(line)
+	if m == nil {
+		return nil, errFormat
+	}
+	ts, err := parseTimestamp(m[1])
+	if err != nil {
+		return nil, err
+	}
+	return &LogEntry{
+		Timestamp: ts,
+		Level:     strings.ToUpper(m[2]),
+		Message:   m[3],
+	}, nil
+}
+
+// parseSyslog accepte RFC 5424 puis RFC 3164
+func parseSyslog(line string) (*LogEntry, error) {
+	if m := syslog5424Regex.FindStringSubmatch(line); m != nil {
+		entry := &LogEntry{
+			Level:   syslogLevel(m[1]),
+			Message: m[8],
+			Fields: map[string]string{
+				"host": m[3],
+				"app":  m[4],
+				"pid":  m[5],
+			},
+		}
+		if m[2] != "-" {
+			ts, err := time.Parse(time.RFC3339Nano, m[2])
+			if err != nil {
+				return nil, fmt.Errorf("date invalide %q", m[2])
+			}
+			entry.Timestamp = ts
+		}
+		return entry, nil
+	}
+
+	m := syslog3164Regex.FindStringSubmatch(line)
+	if m == nil {
+		return nil, errFormat
+	}
+	ts, err := time.ParseInLocation(time.Stamp, m[2], logLocation)
+	if err != nil {
+		return nil, fmt.Errorf("date invalide %q", m[2])
+	}
+
+	entry := &LogEntry{
+		Timestamp: withCurrentYear(ts),
+		Level:     syslogLevel(m[1]),
+		Message:   m[6],
+		Fields: map[string]string{
+			"host": m[3],
+			"app":  m[4],
+			"pid":  m[5],
+		},
+	}
+	if m[1] == "" {
+		entry.Level = detectLevel(m[6])
+	}
+	return entry, nil
+}
+
+// syslogLevel déduit le niveau depuis la priorité <PRI>
+func syslogLevel(pri string) string {
+	value, err := strconv.Atoi(pri)
+	if err != nil {
+		return ""
+	}
+	return syslogSeverities[value%8]
+}
+
+// withCurrentYear complète une date RFC 3164 (sans année)
+func withCurrentYear(ts time.Time) time.Time {
+	now := time.Now()
+	ts = ts.AddDate(now.Year(), 0, 0)
+	// Un log de décembre lu en janvier vient de l'année d'avant
+	if ts.After(now.Add(24 * time.Hour)) {
+		ts = ts.AddDate(-1, 0, 0)
+	}
+	return ts
+}
+
+// parseJSONLine lit un objet JSON par ligne
+func parseJSONLine(line string) (*LogEntry, error) {
+	var object map[string]any
+	if err := json.Unmarshal([]byte(line), &object); err != nil {
+		return nil, fmt.Errorf("JSON invalide: %w", err)
+	}
+
+	entry := &LogEntry{Fields: make(map[string]string, len(object))}
+	for key, value := range object {
+		entry.Fields[key] = jsonString(value)
+	}
+
+	// Une date illisible laisse la ligne sans date plutôt que de la rejeter
+	if value, ok := firstValue(object, jsonTimeKeys); ok {
+		entry.Timestamp, _ = jsonTimestamp(value)
+	}
+	if value, ok := firstField(entry.Fields, jsonLevelKeys); ok {
+		entry.Level = strings.ToUpper(value)
+	}
+	if value, ok := firstField(entry.Fields, jsonMessageKeys); ok {
+		entry.Message = value
+	} else {
+		entry.Message = line
+	}
+	return entry, nil
+}
+
+// jsonString convertit une valeur JSON en texte
+func jsonString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// jsonTimestamp lit une date texte ou un epoch numérique: en millisecondes
+// au-delà de 1e11 (pino: 1696946400000), en secondes sinon (zap: 1.6969464e9)
+func jsonTimestamp(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		ts, err := parseTimestamp(v)
+		return ts, err == nil
+	case float64:
+		if v >= epochMillisThreshold || v <= -epochMillisThreshold {
+			return time.UnixMilli(int64(v)).UTC(), true
+		}
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// Un epoch en secondes ne dépasse 1e11 qu'après l'an 5000
+const epochMillisThreshold = 1e11
+
+// firstValue renvoie la première clé présente et non vide de l'objet JSON
+func firstValue(object map[string]any, keys []string) (any, bool) {
+	for _, key := range keys {
+		if value, ok := object[key]; ok && value != nil && value != "" {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func firstField(fields map[string]string, keys []string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok && value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func parseCustomApp(line string) (*LogEntry, error) {
+	m := customAppRegex.FindStringSubmatch(line)
+	if m == nil {
+		return nil, errFormat
+	}
+	entry := &LogEntry{Level: m[2], Message: m[3]}
+	if m[1] != "" {
+		ts, err := parseTimestamp(m[1])
+		if err != nil {
+			return nil, err
+		}
+		entry.Timestamp = ts
+	}
+	return entry, nil
+}
+
+// parseGeneric accepte toute ligne, le niveau est deviné si présent
+func parseGeneric(line string) (*LogEntry, error) {
+	return &LogEntry{Message: line, Level: detectLevel(line)}, nil
+}
+
+func detectLevel(text string) string {
+	if m := genericLevelRegex.FindStringSubmatch(text); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,279 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		logType string
+		line    string
+
+		time    string // date attendue, voir checkTime (vide: pas de date)
+		layout  string // défaut: RFC 3339, heure locale sans fuseau
+		level   string
+		message string
+		fields  map[string]string
+		http    *HTTPRequest
+		wantErr bool
+	}{
+		{
+			name:    "nginx ident et user",
+			logType: config.TypeNginxAccess,
+			line:    `192.168.1.1 - - [10/Oct/2023:14:00:00 +0000] "GET /index.html HTTP/1.1" 200 1234`,
+			time:    "2023-10-10T14:00:00Z",
+			level:   "INFO",
+			message: "GET /index.html 200",
+			http:    &HTTPRequest{Client: "192.168.1.1", Method: "GET", Path: "/index.html", Status: 200, Bytes: 1234},
+		},
+		{
+			name:    "nginx un seul tiret",
+			logType: config.TypeNginxAccess,
+			line:    `10.0.0.7 - [10/Oct/2023:14:00:01 +0200] "POST /api HTTP/1.1" 503 -`,
+			time:    "2023-10-10T14:00:01+02:00",
+			level:   "ERROR",
+			message: "POST /api 503",
+			http:    &HTTPRequest{Client: "10.0.0.7", Method: "POST", Path: "/api", Status: 503},
+		},
+		{
+			name:    "nginx combined",
+			logType: config.TypeNginxCombined,
+			line:    `10.0.0.7 - bob [10/Oct/2023:14:00:02 +0000] "GET /login HTTP/2.0" 404 512 "https://exemple.fr/" "curl/8.0"`,
+			time:    "2023-10-10T14:00:02Z",
+			level:   "WARN",
+			message: "GET /login 404",
+			fields:  map[string]string{"referer": "https://exemple.fr/", "user_agent": "curl/8.0"},
+			http:    &HTTPRequest{Client: "10.0.0.7", Method: "GET", Path: "/login", Status: 404, Bytes: 512},
+		},
+		{
+			name:    "nginx ligne tronquée",
+			logType: config.TypeNginxAccess,
+			line:    `192.168.1.1 - - [10/Oct/2023:14:00:00 +0000] "GET /index.html`,
+			wantErr: true,
+		},
+		{
+			name:    "syslog RFC 3164",
+			logType: config.TypeSyslog,
+			line:    `<34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed`,
+			time:    "Oct 11 22:14:15",
+			layout:  time.Stamp,
+			level:   "FATAL",
+			message: "'su root' failed",
+			fields:  map[string]string{"host": "mymachine", "app": "su", "pid": "123"},
+		},
+		{
+			name:    "syslog RFC 3164 sans priorité",
+			logType: config.TypeSyslog,
+			line:    `Oct  2 08:00:00 web cron: WARNING disque presque plein`,
+			time:    "Oct  2 08:00:00",
+			layout:  time.Stamp,
+			level:   "WARNING",
+			message: "WARNING disque presque plein",
+			fields:  map[string]string{"host": "web", "app": "cron", "pid": ""},
+		},
+		{
+			name:    "syslog RFC 5424",
+			logType: config.TypeSyslog,
+			line:    `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [exampleSDID@32473 iut="3"] message reçu`,
+			time:    "2003-10-11T22:14:15.003Z",
+			level:   "INFO",
+			message: "message reçu",
+			fields:  map[string]string{"host": "mymachine", "app": "evntslog", "pid": "-"},
+		},
+		{
+			name:    "syslog RFC 5424 sans date ni message",
+			logType: config.TypeSyslog,
+			line:    `<11>1 - host app 42 - -`,
+			level:   "ERROR",
+			fields:  map[string]string{"host": "host", "app": "app", "pid": "42"},
+		},
+		{
+			name:    "syslog illisible",
+			logType: config.TypeSyslog,
+			line:    `pas du syslog`,
+			wantErr: true,
+		},
+		{
+			name:    "mysql 8",
+			logType: config.TypeMySQLError,
+			line:    `2023-10-10T14:00:00.123456Z 0 [ERROR] [MY-010123] [Server] Too many connections`,
+			time:    "2023-10-10T14:00:00.123456Z",
+			level:   "ERROR",
+			message: "[MY-010123] [Server] Too many connections",
+		},
+		{
+			name:    "mysql 5.7",
+			logType: config.TypeMySQLError,
+			line:    `2023-10-10 14:00:00 1234 [Warning] Aborted connection`,
+			time:    "2023-10-10T14:00:00",
+			level:   "WARNING",
+			message: "Aborted connection",
+		},
+		{
+			name:    "json",
+			logType: config.TypeJSONLines,
+			line:    `{"time": "2023-10-10T14:00:00Z", "level": "error", "msg": "timeout", "user": "bob", "retry": 3}`,
+			time:    "2023-10-10T14:00:00Z",
+			level:   "ERROR",
+			message: "timeout",
+			fields: map[string]string{
+				"time": "2023-10-10T14:00:00Z", "level": "error", "msg": "timeout", "user": "bob", "retry": "3",
+			},
+		},
+		{
+			name:    "json sans message",
+			logType: config.TypeJSONLines,
+			line:    `{"severity": "warn"}`,
+			level:   "WARN",
+			message: `{"severity": "warn"}`,
+			fields:  map[string]string{"severity": "warn"},
+		},
+		{
+			name:    "json epoch en millisecondes (pino)",
+			logType: config.TypeJSONLines,
+			line:    `{"level":50,"time":1696946400000,"msg":"boom"}`,
+			time:    "2023-10-10T14:00:00Z",
+			level:   "50",
+			message: "boom",
+			fields:  map[string]string{"level": "50", "time": "1696946400000", "msg": "boom"},
+		},
+		{
+			name:    "json epoch en secondes (zap)",
+			logType: config.TypeJSONLines,
+			line:    `{"level":"info","ts":1.6969464005e9,"msg":"ok"}`,
+			time:    "2023-10-10T14:00:00.5Z",
+			level:   "INFO",
+			message: "ok",
+			fields:  map[string]string{"level": "info", "ts": "1696946400.5", "msg": "ok"},
+		},
+		{
+			name:    "json date illisible",
+			logType: config.TypeJSONLines,
+			line:    `{"time": "hier soir", "msg": "sans date"}`,
+			message: "sans date",
+			fields:  map[string]string{"time": "hier soir", "msg": "sans date"},
+		},
+		{
+			name:    "json date non textuelle",
+			logType: config.TypeJSONLines,
+			line:    `{"time": true, "msg": "sans date"}`,
+			message: "sans date",
+			fields:  map[string]string{"time": "true", "msg": "sans date"},
+		},
+		{
+			name:    "json invalide",
+			logType: config.TypeJSONLines,
+			line:    `{"level": "error"`,
+			wantErr: true,
+		},
+		{
+			name:    "custom-app",
+			logType: config.TypeCustomApp,
+			line:    `2023-10-10 14:00:00 [WARN] User session expired.`,
+			time:    "2023-10-10T14:00:00",
+			level:   "WARN",
+			message: "User session expired.",
+		},
+		{
+			name:    "custom-app décalage sans deux-points",
+			logType: config.TypeCustomApp,
+			line:    `2023-10-10T14:00:00+0000 ERROR boom`,
+			time:    "2023-10-10T14:00:00Z",
+			level:   "ERROR",
+			message: "boom",
+		},
+		{
+			name:    "custom-app décalage sans deux-points, espace",
+			logType: config.TypeCustomApp,
+			line:    `2023-10-10 16:00:00.250+0200 [WARN] lent`,
+			time:    "2023-10-10T16:00:00.25+02:00",
+			level:   "WARN",
+			message: "lent",
+		},
+		{
+			name:    "custom-app sans date",
+			logType: config.TypeCustomApp,
+			line:    `ERROR: Failed to connect to database.`,
+			level:   "ERROR",
+			message: "Failed to connect to database.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, ok := LookupParser(tt.logType)
+			if !ok {
+				t.Fatalf("pas de parser pour %q", tt.logType)
+			}
+			entry, err := parser.Parse(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("aucune erreur, entrée %+v", entry)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkTime(t, entry.Timestamp, tt.time, tt.layout)
+			if entry.Level != tt.level {
+				t.Errorf("niveau %q, attendu %q", entry.Level, tt.level)
+			}
+			if entry.Message != tt.message {
+				t.Errorf("message %q, attendu %q", entry.Message, tt.message)
+			}
+			if fmt.Sprint(entry.Fields) != fmt.Sprint(tt.fields) {
+				t.Errorf("champs %v, attendu %v", entry.Fields, tt.fields)
+			}
+			if fmt.Sprint(entry.HTTP) != fmt.Sprint(tt.http) {
+				t.Errorf("requête %+v, attendu %+v", entry.HTTP, tt.http)
+			}
+		})
+	}
+}
+
+// checkTime compare la date lue à want: vide = pas de date, RFC 3339 avec
+// fuseau, ou sans fuseau pour une date en heure locale. Avec layout (dates sans
+// année), seul l'affichage en heure locale est comparé.
+func checkTime(t *testing.T, got time.Time, want, layout string) {
+	t.Helper()
+	switch {
+	case want == "":
+		if !got.IsZero() {
+			t.Errorf("date %v, attendu aucune", got)
+		}
+		return
+	case layout != "":
+		if got.Location() != time.Local || got.Format(layout) != want {
+			t.Errorf("date %s (%s), attendu %s en heure locale", got.Format(layout), got.Location(), want)
+		}
+		return
+	}
+
+	expected, err := time.Parse(time.RFC3339Nano, want)
+	if err != nil {
+		expected, err = time.ParseInLocation("2006-01-02T15:04:05.999999999", want, time.Local)
+	}
+	if err != nil {
+		t.Fatalf("date attendue illisible %q", want)
+	}
+	if !got.Equal(expected) {
+		t.Errorf("date %s, attendu %s", got.Format(time.RFC3339Nano), expected.Format(time.RFC3339Nano))
+	}
+}
+
+// Chaque type accepté par la config doit avoir un parser (sauf "regex", construit
+// depuis la config)
+func TestKnownTypesHaveParser(t *testing.T) {
+	for _, logType := range config.KnownTypes() {
+		if _, ok := LookupParser(logType); !ok && logType != config.TypeRegex {
+			t.Errorf("type %q accepté par la config sans parser", logType)
+		}
+	}
+}
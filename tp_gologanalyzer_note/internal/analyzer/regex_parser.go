@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Groupes lus par défaut quand le format ne les désigne pas
+var (
+	regexTimeFields    = []string{"time", "timestamp", "ts"}
+	regexLevelFields   = []string{"level", "severity", "lvl"}
+	regexMessageFields = []string{"message", "msg"}
+)
+
+// regexParser applique le format décrit dans la config (type "regex")
+type regexParser struct {
+	format *config.LogFormat
+	regex  *regexp.Regexp
+	names  []string
+}
+
+// newRegexParser prépare le parser; le motif est compilé par config.LoadConfig
+func newRegexParser(format *config.LogFormat) (*regexParser, error) {
+	regex := format.Regex
+	if regex == nil {
+		expanded, err := config.ExpandPattern(format.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		if regex, err = regexp.Compile(expanded); err != nil {
+			return nil, fmt.Errorf("regex invalide: %w", err)
+		}
+	}
+	return &regexParser{format: format, regex: regex, names: regex.SubexpNames()}, nil
+}
+
+func (p *regexParser) Parse(line string) (*LogEntry, error) {
+	m := p.regex.FindStringSubmatch(line)
+	if m == nil {
+		return nil, errFormat
+	}
+
+	entry := &LogEntry{Fields: make(map[string]string, len(m))}
+	for i, name := range p.names {
+		if name != "" && m[i] != "" {
+			entry.Fields[name] = m[i]
+		}
+	}
+
+	if value, ok := formatField(entry.Fields, p.format.TimeField, regexTimeFields); ok {
+		ts, err := p.parseTime(value)
+		if err != nil {
+			return nil, err
+		}
+		entry.Timestamp = ts
+	}
+
+	if value, ok := formatField(entry.Fields, p.format.MessageField, regexMessageFields); ok {
+		entry.Message = value
+	} else {
+		entry.Message = line
+	}
+
+	if value, ok := formatField(entry.Fields, p.format.LevelField, regexLevelFields); ok {
+		entry.Level = p.level(value)
+	} else {
+		entry.Level = detectLevel(entry.Message)
+	}
+	return entry, nil
+}
+
+// parseTime utilise le layout du format, sinon les formats connus
+func (p *regexParser) parseTime(value string) (time.Time, error) {
+	if p.format.TimeLayout == "" {
+		return parseTimestamp(value)
+	}
+	ts, err := time.ParseInLocation(p.format.TimeLayout, value, logLocation)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date invalide %q (layout %q)", value, p.format.TimeLayout)
+	}
+	if ts.Year() == 0 {
+		ts = withCurrentYear(ts)
+	}
+	return ts, nil
+}
+
+// level applique la correspondance du format (valeur exacte puis sans la casse)
+func (p *regexParser) level(value string) string {
+	if level, ok := p.format.Levels[value]; ok {
+		return strings.ToUpper(level)
+	}
+	for raw, level := range p.format.Levels {
+		if strings.EqualFold(raw, value) {
+			return strings.ToUpper(level)
+		}
+	}
+	return strings.ToUpper(value)
+}
+
+// formatField lit le groupe désigné, sinon le premier groupe usuel présent
+func formatField(fields map[string]string, name string, defaults []string) (string, bool) {
+	if name != "" {
+		value, ok := fields[name]
+		return value, ok && value != ""
+	}
+	return firstField(fields, defaults)
+}
+
+// parserFor renvoie le parser d'une config: son format si type "regex",
+// sinon le parser enregistré pour le type
+func parserFor(logConfig config.LogConfig) (Parser, error) {
+	if logConfig.Type == config.TypeRegex {
+		if logConfig.Format == nil {
+			return nil, fmt.Errorf("format manquant pour le type %q", config.TypeRegex)
+		}
+		return newRegexParser(logConfig.Format)
+	}
+	parser, ok := LookupParser(logConfig.Type)
+	if !ok {
+		return nil, fmt.Errorf("aucun parser pour le type %q", logConfig.Type)
+	}
+	return parser, nil
+}
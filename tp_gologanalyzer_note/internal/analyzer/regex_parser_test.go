@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+func TestRegexParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		format config.LogFormat
+		line   string
+
+		time    string // voir checkTime
+		level   string
+		message string
+		fields  map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "groupes par défaut",
+			format:  config.LogFormat{Pattern: `^%{TIMESTAMP_ISO8601:time} %{LOGLEVEL:level} %{GREEDYDATA:message}$`},
+			line:    "2023-10-10T14:00:00Z warn disque plein",
+			time:    "2023-10-10T14:00:00Z",
+			level:   "WARN",
+			message: "disque plein",
+			fields:  map[string]string{"time": "2023-10-10T14:00:00Z", "level": "warn", "message": "disque plein"},
+		},
+		{
+			name:    "décalage sans deux-points",
+			format:  config.LogFormat{Pattern: `^%{TIMESTAMP_ISO8601:ts} %{GREEDYDATA:msg}$`},
+			line:    "2023-10-10T16:00:00+0200 ERROR boom",
+			time:    "2023-10-10T16:00:00+02:00",
+			level:   "ERROR",
+			message: "ERROR boom",
+			fields:  map[string]string{"ts": "2023-10-10T16:00:00+0200", "msg": "ERROR boom"},
+		},
+		{
+			name:    "virgule avant les millisecondes",
+			format:  config.LogFormat{Pattern: `^%{TIMESTAMP_ISO8601:time} %{GREEDYDATA:message}$`},
+			line:    "2023-10-10 14:00:00,250 ok",
+			time:    "2023-10-10T14:00:00.25",
+			message: "ok",
+			fields:  map[string]string{"time": "2023-10-10 14:00:00,250", "message": "ok"},
+		},
+		{
+			name: "groupes désignés et niveaux",
+			format: config.LogFormat{
+				Pattern:      `^(?P<d>\d{2}/\d{2}/\d{4} \d{2}:\d{2}) (?P<sev>[A-Z]) (?P<text>.*)$`,
+				TimeField:    "d",
+				TimeLayout:   "02/01/2006 15:04",
+				LevelField:   "sev",
+				Levels:       map[string]string{"E": "error", "w": "WARN"},
+				MessageField: "text",
+			},
+			line:    "10/10/2023 14:00 W lent",
+			time:    "2023-10-10T14:00:00",
+			level:   "WARN",
+			message: "lent",
+			fields:  map[string]string{"d": "10/10/2023 14:00", "sev": "W", "text": "lent"},
+		},
+		{
+			name:    "sans groupe de date ni de niveau",
+			format:  config.LogFormat{Pattern: `^%{WORD:app}: %{GREEDYDATA:message}$`},
+			line:    "api: ERROR connexion perdue",
+			level:   "ERROR",
+			message: "ERROR connexion perdue",
+			fields:  map[string]string{"app": "api", "message": "ERROR connexion perdue"},
+		},
+		{
+			name:    "sans groupe de message",
+			format:  config.LogFormat{Pattern: `^%{WORD:app} `},
+			line:    "api démarrée",
+			message: "api démarrée",
+			fields:  map[string]string{"app": "api"},
+		},
+		{
+			name:    "ligne non reconnue",
+			format:  config.LogFormat{Pattern: `^%{INT:code} %{GREEDYDATA:message}$`},
+			line:    "pas de code",
+			wantErr: true,
+		},
+		{
+			name:    "date illisible",
+			format:  config.LogFormat{Pattern: `^(?P<time>\S+) %{GREEDYDATA:message}$`, TimeLayout: time.Kitchen},
+			line:    "midi ok",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			parser, err := parserFor(config.LogConfig{Type: config.TypeRegex, Format: &format})
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry, err := parser.Parse(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("aucune erreur, entrée %+v", entry)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkTime(t, entry.Timestamp, tt.time, "")
+			if entry.Level != tt.level {
+				t.Errorf("niveau %q, attendu %q", entry.Level, tt.level)
+			}
+			if entry.Message != tt.message {
+				t.Errorf("message %q, attendu %q", entry.Message, tt.message)
+			}
+			if fmt.Sprint(entry.Fields) != fmt.Sprint(tt.fields) {
+				t.Errorf("champs %v, attendu %v", entry.Fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestParserForRegexErrors(t *testing.T) {
+	for _, logConfig := range []config.LogConfig{
+		{Type: config.TypeRegex},
+		{Type: config.TypeRegex, Format: &config.LogFormat{Pattern: `%{NOPE:x}`}},
+		{Type: config.TypeRegex, Format: &config.LogFormat{Pattern: `(?P<x>`}},
+		{Type: "inconnu"},
+	} {
+		if _, err := parserFor(logConfig); err == nil {
+			t.Errorf("%+v: aucune erreur", logConfig)
+		}
+	}
+}
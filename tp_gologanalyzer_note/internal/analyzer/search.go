@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// SearchOptions règle une recherche dans les logs
+type SearchOptions struct {
+	Pattern *regexp.Regexp
+	// Lignes de contexte avant et après chaque correspondance
+	Context int
+	// Nombre max de correspondances par fichier (0 = pas de limite)
+	MaxMatches int
+	// Comme pour Options
+	Workers int
+	Timeout time.Duration
+}
+
+// SearchLogsConcurrently cherche le motif dans chaque fichier avec le même pool
+// que l'analyse. Les résultats sont dans l'ordre de logConfigs.
+func SearchLogsConcurrently(ctx context.Context, logConfigs []config.LogConfig, opts SearchOptions) []config.SearchResult {
+	results := make([]config.SearchResult, len(logConfigs))
+	runPool(len(logConfigs), opts.Workers, func(i int) {
+		results[i] = searchLogFile(ctx, logConfigs[i], opts)
+	})
+	return results
+}
+
+// searchLogFile cherche dans un fichier en respectant son timeout, même si une
+// lecture reste bloquée (NFS...)
+func searchLogFile(ctx context.Context, logConfig config.LogConfig, opts SearchOptions) config.SearchResult {
+	newResult := func() config.SearchResult {
+		return config.SearchResult{
+			LogID:    logConfig.ID,
+			SourceID: logConfig.SourceID,
+			FilePath: logConfig.Path,
+			Matches:  []config.SearchMatch{},
+		}
+	}
+
+	// found n'est lu qu'une fois la recherche terminée: une lecture bloquée
+	// peut encore le modifier après l'échéance
+	result, found := newResult(), newResult()
+	var scanErr error
+	err := withTimeout(ctx, fileTimeout(logConfig, opts.Timeout), func(ctx context.Context) bool {
+		scanErr = scanMatches(ctx, logConfig, opts, &found)
+		return scanErr == nil
+	})
+	if err == nil {
+		result, err = found, scanErr
+	}
+
+	switch {
+	case err == nil:
+		result.Status = config.StatusOK
+		return result
+	case IsTimeout(err):
+		result.Status = config.StatusTimeout
+		result.ErrorDetails = err.Error()
+	case ctx.Err() != nil:
+		err = ctx.Err()
+		result.Status = config.StatusFailed
+		result.ErrorDetails = "recherche interrompue"
+	default:
+		result.Status = config.StatusFailed
+		result.ErrorDetails = err.Error()
+	}
+	result.ErrorCode = ErrorCode(err)
+	return result
+}
+
+// scanMatches lit le fichier (décompressé si besoin) et garde les lignes trouvées
+func scanMatches(ctx context.Context, logConfig config.LogConfig, opts SearchOptions, result *config.SearchResult) error {
+	info, err := os.Stat(logConfig.Path)
+	if err != nil {
+		return openError(logConfig.Path, err)
+	}
+	if info.IsDir() {
+		return NewIsDirectoryError(logConfig.Path)
+	}
+
+	file, err := os.Open(logConfig.Path)
+	if err != nil {
+		return openError(logConfig.Path, err)
+	}
+	defer file.Close()
+
+	// Fermer le fichier débloque une lecture en cours si ctx est annulé
+	stop := context.AfterFunc(ctx, func() { file.Close() })
+	defer stop()
+
+	stream, err := openLogStream(file)
+	if err != nil {
+		return NewParseError("décompression", err)
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	var before []config.ContextLine // dernières lignes vues, pour le contexte
+	afterLeft := 0                  // lignes de contexte encore dues à la dernière correspondance
+
+	for {
+		if result.LinesScanned%cancelCheckInterval == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return readErr
+		}
+
+		if line != "" {
+			result.LinesScanned++
+			text := strings.TrimRight(line, "\r\n")
+			current := config.ContextLine{Line: result.LinesScanned, Text: text}
+
+			limitReached := opts.MaxMatches > 0 && len(result.Matches) >= opts.MaxMatches
+			switch {
+			case !limitReached && opts.Pattern.MatchString(text):
+				result.Matches = append(result.Matches, config.SearchMatch{
+					Line:   current.Line,
+					Text:   text,
+					Before: before,
+				})
+				before = nil
+				afterLeft = opts.Context
+			case afterLeft > 0:
+				last := &result.Matches[len(result.Matches)-1]
+				last.After = append(last.After, current)
+				afterLeft--
+			case opts.Context > 0:
+				before = append(before, current)
+				if len(before) > opts.Context {
+					before = before[1:]
+				}
+			}
+
+			// Limite atteinte et contexte complet: inutile de lire la suite
+			if opts.MaxMatches > 0 && len(result.Matches) >= opts.MaxMatches && afterLeft == 0 {
+				result.Truncated = readErr != io.EOF
+				return nil
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,86 @@
+//go:build unix
+
+package analyzer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// blockedFile crée un tube nommé sans écrivain: son ouverture en lecture bloque,
+// comme un montage NFS qui ne répond plus
+func blockedFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "nfs.log")
+	if err := syscall.Mkfifo(path, 0644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	t.Cleanup(func() {
+		// Un écrivain débloque les lectures abandonnées
+		if writer, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			writer.Close()
+		}
+	})
+	return path
+}
+
+func TestSearchLogFile(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	writeFile(t, logPath, lineA+lineB+lineC)
+
+	tests := []struct {
+		name    string
+		path    string
+		status  string
+		code    string
+		matches int
+	}{
+		{name: "correspondance", path: logPath, status: config.StatusOK, matches: 1},
+		{name: "fichier introuvable", path: filepath.Join(dir, "absent.log"),
+			status: config.StatusFailed, code: config.ErrorCodeNotFound},
+		{name: "répertoire", path: dir, status: config.StatusFailed, code: config.ErrorCodeIsDirectory},
+		{name: "archive corrompue", path: filepath.Join(dir, "bad.log.gz"),
+			status: config.StatusFailed, code: config.ErrorCodeParse},
+		{name: "lecture bloquée", path: blockedFile(t), status: config.StatusTimeout, code: config.ErrorCodeTimeout},
+	}
+	writeFile(t, filepath.Join(dir, "bad.log.gz"), "\x1f\x8b\x09\x00\x00\x00\x00\x00\x00\x03 pas du gzip")
+
+	opts := SearchOptions{Pattern: regexp.MustCompile("ERROR"), Timeout: 100 * time.Millisecond}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			result := searchLogFile(context.Background(), config.LogConfig{ID: "app", Path: tt.path}, opts)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("recherche rendue après %s malgré le timeout", elapsed)
+			}
+			if result.Status != tt.status || result.ErrorCode != tt.code {
+				t.Errorf("status %s [%s] %s, attendu %s [%s]",
+					result.Status, result.ErrorCode, result.ErrorDetails, tt.status, tt.code)
+			}
+			if len(result.Matches) != tt.matches {
+				t.Errorf("%d correspondance(s), attendu %d", len(result.Matches), tt.matches)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTimeoutBlockedRead(t *testing.T) {
+	logConfig := config.LogConfig{ID: "nfs", Path: blockedFile(t), Type: config.TypeGeneric}
+	start := time.Now()
+	result := analyzeWithTimeout(context.Background(), logConfig, Options{Timeout: 100 * time.Millisecond})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("analyse rendue après %s malgré le timeout", elapsed)
+	}
+	if result.Status != config.StatusTimeout || result.ErrorCode != config.ErrorCodeTimeout {
+		t.Errorf("status %s [%s], attendu %s [%s]",
+			result.Status, result.ErrorCode, config.StatusTimeout, config.ErrorCodeTimeout)
+	}
+}
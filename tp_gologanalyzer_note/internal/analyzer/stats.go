@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Nombre d'entrées gardées dans les classements (top erreurs...)
+const DefaultTopN = 5
+
+// Limite de valeurs distinctes suivies pour un classement
+const maxTrackedValues = 10000
+
+// Niveaux normalisés
+const (
+	LevelDebug = "DEBUG"
+	LevelInfo  = "INFO"
+	LevelWarn  = "WARN"
+	LevelError = "ERROR"
+	LevelFatal = "FATAL"
+)
+
+// fileStats accumule les statistiques d'un fichier pendant le parsing.
+// Les champs sont exportés pour être sauvegardés dans les checkpoints.
+type fileStats struct {
+	Levels        config.LevelCounts `json:"levels"`
+	First         time.Time          `json:"first"`
+	Last          time.Time          `json:"last"`
+	ErrorMessages map[string]int     `json:"error_messages"`
+
+	// nil tant qu'aucune requête HTTP n'a été vue
+	Access  *config.AccessStats `json:"access,omitempty"`
+	Paths   map[string]int      `json:"paths,omitempty"`
+	Clients map[string]int      `json:"clients,omitempty"`
+
+	// nil si le regroupement par motif n'est pas activé
+	Clusters        map[string]clusterStats `json:"clusters,omitempty"`
+	ClustersDropped int                     `json:"clusters_dropped,omitempty"`
+
+	// nil si l'histogramme n'est pas activé
+	Timeline       map[int64]timelineBucket `json:"timeline,omitempty"`
+	TimelineBucket string                   `json:"timeline_bucket,omitempty"`
+}
+
+func newFileStats() *fileStats {
+	return &fileStats{ErrorMessages: make(map[string]int)}
+}
+
+// add prend en compte une ligne parsée
+func (s *fileStats) add(entry *LogEntry) {
+	if !entry.Timestamp.IsZero() {
+		if s.First.IsZero() || entry.Timestamp.Before(s.First) {
+			s.First = entry.Timestamp
+		}
+		if entry.Timestamp.After(s.Last) {
+			s.Last = entry.Timestamp
+		}
+	}
+
+	if entry.HTTP != nil {
+		s.addRequest(entry.HTTP)
+	}
+
+	if s.Clusters != nil {
+		s.addCluster(entry)
+	}
+
+	level := NormalizeLevel(entry.Level)
+	if s.Timeline != nil && !entry.Timestamp.IsZero() {
+		s.addTimeline(entry, level == LevelError || level == LevelFatal)
+	}
+
+	switch level {
+	case LevelDebug:
+		s.Levels.Debug++
+	case LevelInfo:
+		s.Levels.Info++
+	case LevelWarn:
+		s.Levels.Warn++
+	case LevelError:
+		s.Levels.Error++
+		countValue(s.ErrorMessages, entry.Message)
+	case LevelFatal:
+		s.Levels.Fatal++
+		countValue(s.ErrorMessages, entry.Message)
+	}
+}
+
+// fill copie les statistiques dans le résultat
+func (s *fileStats) fill(result *config.AnalysisResult) {
+	levels := s.Levels
+	result.Levels = &levels
+
+	if !s.First.IsZero() {
+		first, last := s.First, s.Last
+		result.FirstSeen = &first
+		result.LastSeen = &last
+	}
+	result.TopErrors = topEntries(s.ErrorMessages, DefaultTopN)
+
+	if s.Access != nil {
+		access := *s.Access
+		access.TopPaths = topEntries(s.Paths, DefaultTopN)
+		access.TopClients = topEntries(s.Clients, DefaultTopN)
+		result.Access = &access
+	}
+	s.fillClusters(result)
+	s.fillTimeline(result)
+}
+
+// addRequest met à jour les statistiques de trafic
+func (s *fileStats) addRequest(request *HTTPRequest) {
+	if s.Access == nil {
+		s.Access = &config.AccessStats{
+			StatusCodes: make(map[string]int),
+			Methods:     make(map[string]int),
+		}
+		s.Paths = make(map[string]int)
+		s.Clients = make(map[string]int)
+	}
+
+	access := s.Access
+	access.Requests++
+	access.BytesServed += request.Bytes
+	access.StatusCodes[strconv.Itoa(request.Status)]++
+	access.Methods[request.Method]++
+
+	switch request.Status / 100 {
+	case 1:
+		access.StatusClasses.Informational++
+	case 2:
+		access.StatusClasses.Success++
+	case 3:
+		access.StatusClasses.Redirection++
+	case 4:
+		access.StatusClasses.ClientError++
+	case 5:
+		access.StatusClasses.ServerError++
+	}
+
+	// Les paramètres de requête ne comptent pas dans le classement
+	path, _, _ := strings.Cut(request.Path, "?")
+	countValue(s.Paths, path)
+	countValue(s.Clients, request.Client)
+}
+
+// NormalizeLevel ramène les variantes (WARNING, ERR, CRIT...) aux 5 niveaux
+func NormalizeLevel(level string) string {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "TRACE", "DEBUG", "DBG":
+		return LevelDebug
+	case "INFO", "INFORMATION", "NOTICE", "NOTE", "SYSTEM":
+		return LevelInfo
+	case "WARN", "WARNING":
+		return LevelWarn
+	case "ERROR", "ERR":
+		return LevelError
+	case "FATAL", "CRITICAL", "CRIT", "ALERT", "EMERG", "EMERGENCY", "PANIC":
+		return LevelFatal
+	}
+	return ""
+}
+
+// countValue incrémente un compteur sans dépasser maxTrackedValues clés
+func countValue(counts map[string]int, value string) {
+	if _, exists := counts[value]; exists || len(counts) < maxTrackedValues {
+		counts[value]++
+	}
+}
+
+// topEntries renvoie les n valeurs les plus fréquentes
+func topEntries(counts map[string]int, n int) []config.TopEntry {
+	entries := make([]config.TopEntry, 0, len(counts))
+	for value, count := range counts {
+		entries = append(entries, config.TopEntry{Value: value, Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// clone copie les statistiques (maps comprises)
+func (s *fileStats) clone() *fileStats {
+	copied := *s
+	copied.ErrorMessages = maps.Clone(s.ErrorMessages)
+	copied.Paths = maps.Clone(s.Paths)
+	copied.Clients = maps.Clone(s.Clients)
+	copied.Clusters = cloneClusters(s.Clusters)
+	copied.Timeline = cloneTimeline(s.Timeline)
+	if s.Access != nil {
+		access := *s.Access
+		access.StatusCodes = maps.Clone(s.Access.StatusCodes)
+		access.Methods = maps.Clone(s.Access.Methods)
+		copied.Access = &access
+	}
+	return &copied
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"maps"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Nombre max de tranches dans un histogramme (≈ 35 jours par minute)
+const maxTimelineBuckets = 50000
+
+// timelineBucket compte les lignes d'une tranche
+type timelineBucket struct {
+	Lines  int `json:"lines"`
+	Errors int `json:"errors"`
+}
+
+// enableTimeline active l'histogramme; un changement de tranche repart de zéro
+func (s *fileStats) enableTimeline(bucket string) {
+	if s.Timeline == nil || s.TimelineBucket != bucket {
+		s.Timeline = make(map[int64]timelineBucket)
+		s.TimelineBucket = bucket
+	}
+}
+
+// addTimeline compte la ligne dans sa tranche (clé = début de tranche en secondes Unix)
+func (s *fileStats) addTimeline(entry *LogEntry, isError bool) {
+	size, _ := config.TimelineBucket(s.TimelineBucket)
+	key := entry.Timestamp.Truncate(size).Unix()
+
+	bucket, exists := s.Timeline[key]
+	if !exists && len(s.Timeline) >= maxTimelineBuckets {
+		return
+	}
+	bucket.Lines++
+	if isError {
+		bucket.Errors++
+	}
+	s.Timeline[key] = bucket
+}
+
+// fillTimeline transforme les tranches en tableaux continus (tranches vides à 0)
+func (s *fileStats) fillTimeline(result *config.AnalysisResult) {
+	if s.Timeline == nil || s.First.IsZero() {
+		return
+	}
+	size, _ := config.TimelineBucket(s.TimelineBucket)
+	first := s.First.Truncate(size)
+	last := s.Last.Truncate(size)
+
+	timeline := &config.Timeline{Bucket: s.TimelineBucket, Start: first.UTC()}
+	count := int(last.Sub(first)/size) + 1
+	if count > maxTimelineBuckets {
+		count = maxTimelineBuckets
+		timeline.Start = last.Add(-time.Duration(count-1) * size).UTC()
+		timeline.Truncated = true
+	}
+
+	timeline.Lines = make([]int, count)
+	timeline.Errors = make([]int, count)
+	for key, bucket := range s.Timeline {
+		i := int(time.Unix(key, 0).Sub(timeline.Start) / size)
+		if i < 0 || i >= count {
+			continue
+		}
+		timeline.Lines[i] = bucket.Lines
+		timeline.Errors[i] = bucket.Errors
+	}
+	result.Timeline = timeline
+}
+
+func cloneTimeline(timeline map[int64]timelineBucket) map[int64]timelineBucket {
+	return maps.Clone(timeline)
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// En dessous de cet écart, la recherche s'arrête et la lecture devient séquentielle
+const seekMinSpan = 64 * 1024
+
+// Nombre max de lignes lues pour trouver une date à partir d'un offset
+const seekProbeLines = 64
+
+// seekWindowStart cherche par dichotomie un début de ligne situé avant la première
+// ligne datée >= since. Le fichier doit être trié par date (LogConfig.Sorted).
+func seekWindowStart(file *os.File, size int64, parser Parser, since time.Time) (int64, error) {
+	low, high := int64(0), size
+	for high-low > seekMinSpan {
+		mid := low + (high-low)/2
+		ts, found, err := probeTimestamp(file, mid, high, parser)
+		if err != nil {
+			return 0, err
+		}
+		// Sans date trouvée on reste prudent: on lira plus
+		if found && ts.Before(since) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return nextLineStart(file, low)
+}
+
+// probeTimestamp renvoie la date de la première ligne datée après offset (et avant limit)
+func probeTimestamp(file *os.File, offset, limit int64, parser Parser) (time.Time, bool, error) {
+	start, err := nextLineStart(file, offset)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, start, limit-start))
+	for i := 0; i < seekProbeLines; i++ {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			entry, parseErr := parser.Parse(strings.TrimRight(line, "\r\n"))
+			if parseErr == nil && !entry.Timestamp.IsZero() {
+				return entry.Timestamp, true, nil
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+// nextLineStart renvoie le début de la ligne suivant offset (offset s'il en est un)
+func nextLineStart(file *os.File, offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+	reader := bufio.NewReader(io.NewSectionReader(file, offset-1, 1<<62))
+	skipped, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	return offset - 1 + int64(len(skipped)), nil
+}
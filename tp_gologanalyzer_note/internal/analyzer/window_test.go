@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Début du log trié généré par writeSortedLog; ses dates sont sans fuseau,
+// donc en heure locale comme --since
+var sortedLogStart = time.Date(2025, 9, 24, 0, 0, 0, 0, time.Local)
+
+// writeSortedLog écrit un log trié d'une ligne par seconde (plusieurs Mo),
+// avec des lignes sans date régulières et un bloc sans date au milieu du fichier
+// pour que certaines sondes de la dichotomie ne trouvent aucune date
+func writeSortedLog(t *testing.T, path string, lines int) {
+	t.Helper()
+	var b strings.Builder
+	for i := 0; i < lines; i++ {
+		switch {
+		case i >= lines/2 && i < lines/2+2*seekProbeLines:
+			b.WriteString("    at com.example.Worker.run(Worker.java:42)\n")
+			continue
+		case i%17 == 0:
+			b.WriteString("[INFO] ligne sans date\n")
+			continue
+		}
+		level := "INFO"
+		if i%5 == 0 {
+			level = "ERROR"
+		}
+		ts := sortedLogStart.Add(time.Duration(i) * time.Second)
+		fmt.Fprintf(&b, "%s [%s] requête %d traitée\n", ts.Format("2006-01-02 15:04:05"), level, i)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSortedSeekMatchesFullRead(t *testing.T) {
+	const lines = 60000
+	path := filepath.Join(t.TempDir(), "sorted.log")
+	writeSortedLog(t, path, lines)
+	at := func(i int) time.Time { return sortedLogStart.Add(time.Duration(i) * time.Second) }
+
+	tests := []struct {
+		name   string
+		window config.TimeWindow
+		skips  bool // la recherche doit sauter une partie du fichier
+	}{
+		{name: "since avant la première ligne", window: config.TimeWindow{Since: at(-3600)}},
+		{name: "since après la dernière ligne", window: config.TimeWindow{Since: at(lines + 3600)}, skips: true},
+		{name: "since au milieu", window: config.TimeWindow{Since: at(lines / 3)}, skips: true},
+		{name: "since et until", window: config.TimeWindow{Since: at(lines / 4), Until: at(lines / 3)}, skips: true},
+		{name: "since dans le bloc sans date", window: config.TimeWindow{Since: at(lines/2 + 10)}, skips: true},
+		{name: "since juste après le bloc sans date", window: config.TimeWindow{Since: at(lines/2 + 2*seekProbeLines)}, skips: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Window: tt.window}
+			full := analyzeLogFile(context.Background(),
+				config.LogConfig{ID: "full", Path: path, Type: "custom-app"}, opts)
+			sorted := analyzeLogFile(context.Background(),
+				config.LogConfig{ID: "sorted", Path: path, Type: "custom-app", Sorted: true}, opts)
+
+			if full.Status != config.StatusOK || sorted.Status != config.StatusOK {
+				t.Fatalf("status %s / %s: %s %s", full.Status, sorted.Status, full.ErrorDetails, sorted.ErrorDetails)
+			}
+			if sorted.ParsedLines != full.ParsedLines {
+				t.Errorf("parsed_lines = %d, lecture complète: %d", sorted.ParsedLines, full.ParsedLines)
+			}
+			if fmt.Sprint(sorted.Levels) != fmt.Sprint(full.Levels) {
+				t.Errorf("levels = %+v, lecture complète: %+v", sorted.Levels, full.Levels)
+			}
+			if fmt.Sprint(sorted.FirstSeen, sorted.LastSeen) != fmt.Sprint(full.FirstSeen, full.LastSeen) {
+				t.Errorf("période %v -> %v, lecture complète: %v -> %v",
+					sorted.FirstSeen, sorted.LastSeen, full.FirstSeen, full.LastSeen)
+			}
+			if tt.skips && sorted.SkippedBytes == 0 {
+				t.Errorf("aucun octet sauté")
+			}
+			if !tt.skips && sorted.SkippedBytes != 0 {
+				t.Errorf("%d octets sautés, attendu 0", sorted.SkippedBytes)
+			}
+
+			// Lecture partielle signalée dès qu'une partie du fichier n'est pas lue
+			partial := tt.skips || !tt.window.Until.IsZero()
+			if sorted.PartialCounts != partial || full.PartialCounts {
+				t.Errorf("partial_counts = %v (lecture complète: %v), attendu %v",
+					sorted.PartialCounts, full.PartialCounts, partial)
+			}
+			if !partial && sorted.TotalLines != full.TotalLines {
+				t.Errorf("total_lines = %d, lecture complète: %d", sorted.TotalLines, full.TotalLines)
+			}
+		})
+	}
+}
+
+func TestProbeTimestampUndatedLines(t *testing.T) {
+	const lines = 2000
+	path := filepath.Join(t.TempDir(), "sorted.log")
+	writeSortedLog(t, path, lines)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	parser, _ := LookupParser("custom-app")
+	size := int64(len(data))
+
+	// Au milieu du bloc sans date: aucune date dans les lignes sondées
+	block := int64(strings.Index(string(data), "    at com.example"))
+	if _, found, err := probeTimestamp(file, block+10, size, parser); err != nil || found {
+		t.Errorf("sonde dans le bloc sans date: found=%v err=%v", found, err)
+	}
+
+	// Au milieu d'une ligne: la sonde part de la ligne suivante et saute les lignes sans date
+	offset := int64(strings.Index(string(data), "requête 16 ")) // ligne 17 sans date juste après
+	ts, found, err := probeTimestamp(file, offset, size, parser)
+	if err != nil || !found || !ts.Equal(sortedLogStart.Add(18*time.Second)) {
+		t.Errorf("sonde après la ligne 16: %v found=%v err=%v", ts, found, err)
+	}
+
+	// La recherche ne dépasse jamais la première ligne de la fenêtre
+	for _, i := range []int{0, 1, lines / 2, lines/2 + 2*seekProbeLines + 1, lines - 1} {
+		since := sortedLogStart.Add(time.Duration(i) * time.Second)
+		start, err := seekWindowStart(file, size, parser, since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first := strings.Index(string(data), since.Format("2006-01-02 15:04:05"))
+		if first >= 0 && start > int64(first) {
+			t.Errorf("since ligne %d: départ %d après la première ligne de la fenêtre (%d)", i, start, first)
+		}
+	}
+}
+
+// Une borne sans fuseau et les dates sans fuseau du log sont lues dans le même
+// fuseau: la fenêtre tombe sur les mêmes lignes quel que soit TZ
+func TestWindowBoundSameLocationAsLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeFile(t, path, lineA+lineB+lineC) // 10:00:00, 10:00:01, 10:00:02 sans fuseau
+
+	since, err := config.ParseWindowBound("2025-09-24 10:00:01", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := analyzeLogFile(context.Background(),
+		config.LogConfig{ID: "app", Path: path, Type: "custom-app"},
+		Options{Window: config.TimeWindow{Since: since}})
+
+	if result.ParsedLines != 2 || result.OutOfWindow != 1 {
+		t.Errorf("parsed_lines = %d, out_of_window = %d, attendu 2 et 1", result.ParsedLines, result.OutOfWindow)
+	}
+}
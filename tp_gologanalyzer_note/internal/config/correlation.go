@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Corrélation entre fichiers depuis la config, ex:
+// { "field": "request_id" } ou { "field": "rid", "pattern": "rid=(\\S+)" }
+type Correlation struct {
+	// Champ portant l'ID (clé JSON, ou clé=valeur dans la ligne)
+	Field string `json:"field"`
+	// Regex avec un groupe capturant l'ID dans la ligne brute (optionnel)
+	Pattern string `json:"pattern,omitempty"`
+
+	// Regex compilée au chargement
+	Regex *regexp.Regexp `json:"-"`
+}
+
+// CorrelationPattern construit la regex par défaut d'un champ:
+// request_id=abc, request_id: abc, "request_id": "abc"
+func CorrelationPattern(field string) string {
+	return `(?:^|[\s,;{(\[])"?` + regexp.QuoteMeta(field) + `"?\s*[=:]\s*"?([^\s",;})\]]+)`
+}
+
+// validateCorrelation vérifie le champ et compile la regex
+func (v *validator) validateCorrelation(correlation *Correlation) {
+	if correlation.Field == "" {
+		v.addf("correlation.field", "champ manquant")
+		return
+	}
+
+	pattern := correlation.Pattern
+	if pattern == "" {
+		pattern = CorrelationPattern(correlation.Field)
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		v.addf("correlation.pattern", "regex invalide: %v", err)
+		return
+	}
+	if regex.NumSubexp() != 1 {
+		v.addf("correlation.pattern", "la regex doit avoir exactement un groupe capturant (%d trouvés)", regex.NumSubexp())
+		return
+	}
+	correlation.Regex = regex
+}
+
+// Rapport de corrélation entre fichiers
+type CorrelationReport struct {
+	Field string `json:"field"`
+
+	// IDs distincts vus, IDs présents dans plusieurs logs
+	Requests int `json:"requests"`
+	Joined   int `json:"joined"`
+
+	// Requêtes en erreur côté backend, dont sans entrée access log
+	Errored   int `json:"errored"`
+	Unmatched int `json:"unmatched"`
+
+	// IDs ignorés au-delà de la limite mémoire
+	Dropped int `json:"dropped,omitempty"`
+
+	// Logs repris depuis un checkpoint: seules leurs nouvelles lignes sont
+	// corrélées, les chaînes commencées lors d'un run précédent sont coupées
+	ResumedLogs []string `json:"resumed_logs,omitempty"`
+
+	// Requêtes en erreur les plus lentes
+	ErroredRequests []CorrelatedRequest `json:"errored_requests,omitempty"`
+}
+
+// Requête suivie à travers les logs
+type CorrelatedRequest struct {
+	ID   string   `json:"id"`
+	Logs []string `json:"logs"`
+
+	// Première et dernière ligne datée, toutes sources confondues
+	Start     time.Time `json:"start,omitzero"`
+	End       time.Time `json:"end,omitzero"`
+	LatencyMs int64     `json:"latency_ms"`
+
+	// Première erreur backend et nombre total d'erreurs
+	Error  CorrelatedEvent `json:"error"`
+	Errors int             `json:"errors"`
+
+	// Entrée de l'access log (nil si aucune)
+	Access *CorrelatedEvent `json:"access,omitempty"`
+}
+
+// Ligne d'un log rattachée à une requête
+type CorrelatedEvent struct {
+	LogID     string    `json:"log_id"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Level     string    `json:"level,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// String résume la corrélation
+func (r CorrelationReport) String() string {
+	return fmt.Sprintf("%d requêtes (%d dans plusieurs logs), %d en erreur (%d sans access log)",
+		r.Requests, r.Joined, r.Errored, r.Unmatched)
+}
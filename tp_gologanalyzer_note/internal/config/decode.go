@@ -0,0 +1,369 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats de fichier de config, déduits de l'extension
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+)
+
+func configFormat(configPath string) string {
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".yaml", ".yml":
+		return formatYAML
+	case ".toml":
+		return formatTOML
+	}
+	return formatJSON
+}
+
+// decodeConfig lit la config dans son format. lines donne la ligne de chaque
+// entrée et de chaque champ ("logs[2]", "logs[2].type"). Les timeouts invalides
+// sont renvoyés comme problèmes pour que la validation continue sans eux.
+func decodeConfig(data []byte, format string) (cfg Config, lines map[string]int, problems []ConfigProblem, err error) {
+	switch format {
+	case formatYAML:
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return cfg, nil, nil, fmt.Errorf("erreur parsing YAML: %w", err)
+		}
+		var value any
+		if err := root.Decode(&value); err != nil {
+			return cfg, nil, nil, fmt.Errorf("erreur parsing YAML: %w", err)
+		}
+		lines = yamlLines(&root)
+		cfg, problems, err = decodeValue(value, lines)
+		return cfg, lines, problems, err
+
+	case formatTOML:
+		var value map[string]any
+		if _, err := toml.Decode(string(data), &value); err != nil {
+			return cfg, nil, nil, fmt.Errorf("erreur parsing TOML: %w", err)
+		}
+		lines = tomlLines(data)
+		cfg, problems, err = decodeValue(value, lines)
+		return cfg, lines, problems, err
+	}
+
+	lines = jsonLines(data)
+	if err := decodeJSON(data, &cfg); err != nil {
+		var durationErr *DurationError
+		if errors.As(err, &durationErr) {
+			var value any
+			json.Unmarshal(data, &value)
+			cfg, problems, err = decodeValue(value, lines)
+			return cfg, lines, problems, err
+		}
+		return cfg, lines, nil, fmt.Errorf("erreur parsing JSON: %w", jsonErrorWithLine(data, err))
+	}
+	return cfg, lines, nil, nil
+}
+
+// decodeJSON accepte l'objet complet ou l'ancien tableau de logs
+func decodeJSON(data []byte, cfg *Config) error {
+	if data[0] == '[' {
+		return json.Unmarshal(data, &cfg.Logs)
+	}
+	return json.Unmarshal(data, cfg)
+}
+
+// decodeGeneric repasse par JSON pour réutiliser les mêmes tags et types (Duration)
+func decodeGeneric(value any) (Config, error) {
+	var cfg Config
+	data, err := json.Marshal(value)
+	if err != nil {
+		return cfg, fmt.Errorf("config illisible: %w", err)
+	}
+	if len(data) == 0 || data[0] == 'n' {
+		return cfg, nil
+	}
+	if data[0] != '[' && data[0] != '{' {
+		return cfg, fmt.Errorf("config invalide: objet ou liste attendu")
+	}
+	if err := decodeJSON(data, &cfg); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return cfg, fmt.Errorf("%s: type %s attendu", typeErr.Field, typeErr.Type)
+		}
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// decodeValue décode la config; si un timeout est invalide, il est retiré et
+// signalé comme problème, puis la config est décodée sans lui
+func decodeValue(value any, lines map[string]int) (Config, []ConfigProblem, error) {
+	cfg, err := decodeGeneric(value)
+	var durationErr *DurationError
+	if !errors.As(err, &durationErr) {
+		return cfg, nil, err
+	}
+	problems := removeInvalidDurations(value, lines)
+	if len(problems) == 0 {
+		return cfg, nil, err
+	}
+	cfg, err = decodeGeneric(value)
+	return cfg, problems, err
+}
+
+// removeInvalidDurations retire les timeouts invalides (settings et logs) et
+// renvoie leur champ et leur ligne: le décodeur JSON ne dit pas quel champ a
+// refusé la durée, on les revérifie un par un
+func removeInvalidDurations(value any, lines map[string]int) []ConfigProblem {
+	var problems []ConfigProblem
+	check := func(name string, object any) {
+		entry, _ := object.(map[string]any)
+		timeout, ok := entry["timeout"]
+		if !ok {
+			return
+		}
+		data, _ := json.Marshal(timeout)
+		var d Duration
+		if err := d.UnmarshalJSON(data); err != nil {
+			field := name + ".timeout"
+			problems = append(problems, ConfigProblem{Field: field, Line: lines[field], Message: err.Error()})
+			delete(entry, "timeout")
+		}
+	}
+
+	logs := value
+	if root, ok := value.(map[string]any); ok {
+		check("settings", root["settings"])
+		logs = root["logs"]
+	}
+	switch list := logs.(type) {
+	case []any:
+		for i, entry := range list {
+			check(fmt.Sprintf("logs[%d]", i), entry)
+		}
+	case []map[string]any: // [[logs]] en TOML
+		for i, entry := range list {
+			check(fmt.Sprintf("logs[%d]", i), entry)
+		}
+	}
+	return problems
+}
+
+// jsonErrorWithLine ajoute la ligne aux erreurs de syntaxe et de type
+func jsonErrorWithLine(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("ligne %d: %w", lineAt(data, syntaxErr.Offset), err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("ligne %d, %s: type %s attendu, %s trouvé",
+			lineAt(data, typeErr.Offset), typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	return err
+}
+
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return 1 + bytes.Count(data[:offset], []byte("\n"))
+}
+
+// jsonLines repère la ligne des entrées de logs/rules, de leurs champs, des settings
+// et de la corrélation
+func jsonLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return lines
+	}
+	if token == json.Delim('[') {
+		jsonListLines(decoder, data, "logs", lines)
+		return lines
+	}
+	if token != json.Delim('{') {
+		return lines
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return lines
+		}
+		key, _ := token.(string)
+		if key == "logs" || key == "rules" {
+			lines[key] = lineAt(data, nextValueOffset(data, decoder.InputOffset()))
+			if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+				return lines
+			}
+			if !jsonListLines(decoder, data, key, lines) {
+				return lines
+			}
+			continue
+		}
+		if key == "settings" || key == "correlation" {
+			lines[key] = lineAt(data, nextValueOffset(data, decoder.InputOffset()))
+			if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+				return lines
+			}
+			if !jsonObjectLines(decoder, data, key, lines) {
+				return lines
+			}
+			continue
+		}
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return lines
+		}
+	}
+	return lines
+}
+
+// jsonListLines parcourt un tableau d'objets (le '[' est déjà lu)
+func jsonListLines(decoder *json.Decoder, data []byte, name string, lines map[string]int) bool {
+	for i := 0; decoder.More(); i++ {
+		entry := fmt.Sprintf("%s[%d]", name, i)
+		lines[entry] = lineAt(data, nextValueOffset(data, decoder.InputOffset()))
+
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if delim, ok := token.(json.Delim); ok && delim != '{' {
+			return false // tableau imbriqué: on arrête le repérage
+		}
+		if token != json.Delim('{') {
+			continue
+		}
+		if !jsonObjectLines(decoder, data, entry, lines) {
+			return false
+		}
+	}
+	_, err := decoder.Token() // ']'
+	return err == nil
+}
+
+// jsonObjectLines note la ligne de chaque clé d'un objet (le '{' est déjà lu)
+func jsonObjectLines(decoder *json.Decoder, data []byte, name string, lines map[string]int) bool {
+	for decoder.More() {
+		offset := nextValueOffset(data, decoder.InputOffset())
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		key, _ := token.(string)
+		lines[name+"."+key] = lineAt(data, offset)
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return false
+		}
+	}
+	_, err := decoder.Token() // '}'
+	return err == nil
+}
+
+// nextValueOffset saute les blancs, virgules et deux-points avant la valeur suivante
+func nextValueOffset(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// yamlLines fait la même chose que jsonLines à partir de l'arbre YAML
+func yamlLines(root *yaml.Node) map[string]int {
+	lines := make(map[string]int)
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return lines
+	}
+
+	document := root.Content[0]
+	switch document.Kind {
+	case yaml.SequenceNode:
+		yamlListLines(document, "logs", lines)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(document.Content); i += 2 {
+			key, value := document.Content[i], document.Content[i+1]
+			lines[key.Value] = key.Line
+			switch key.Value {
+			case "logs", "rules":
+				yamlListLines(value, key.Value, lines)
+			case "settings", "correlation":
+				yamlObjectLines(value, key.Value, lines)
+			}
+		}
+	}
+	return lines
+}
+
+func yamlListLines(list *yaml.Node, name string, lines map[string]int) {
+	if list.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, item := range list.Content {
+		entry := fmt.Sprintf("%s[%d]", name, i)
+		lines[entry] = item.Line
+		yamlObjectLines(item, entry, lines)
+	}
+}
+
+func yamlObjectLines(object *yaml.Node, name string, lines map[string]int) {
+	if object.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(object.Content); i += 2 {
+		lines[name+"."+object.Content[i].Value] = object.Content[i].Line
+	}
+}
+
+// tomlLines fait la même chose ligne par ligne pour le TOML, dont le décodeur ne
+// donne pas les positions: tables [settings] et [correlation], tableaux
+// [[logs]] et [[rules]] et leurs clés. Les tableaux en ligne
+// (logs = [{...}]) et les chaînes multilignes ne sont pas repérés.
+func tomlLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	counts := make(map[string]int)
+	current := ""
+
+	for i, text := range strings.Split(string(data), "\n") {
+		text = strings.TrimSpace(text)
+		number := i + 1
+		switch {
+		case strings.HasPrefix(text, "[["):
+			name, _, _ := strings.Cut(text[2:], "]]")
+			name = strings.TrimSpace(name)
+			current = ""
+			if name == "logs" || name == "rules" {
+				if _, seen := lines[name]; !seen {
+					lines[name] = number
+				}
+				current = fmt.Sprintf("%s[%d]", name, counts[name])
+				counts[name]++
+				lines[current] = number
+			}
+		case strings.HasPrefix(text, "["):
+			name, _, _ := strings.Cut(text[1:], "]")
+			name = strings.TrimSpace(name)
+			current = ""
+			if name == "settings" || name == "correlation" {
+				current = name
+				lines[name] = number
+			}
+		case current != "" && !strings.HasPrefix(text, "#"):
+			if key, _, ok := strings.Cut(text, "="); ok {
+				lines[current+"."+strings.Trim(strings.TrimSpace(key), `"'`)] = number
+			}
+		}
+	}
+	return lines
+}
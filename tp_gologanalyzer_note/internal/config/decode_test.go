@@ -0,0 +1,315 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeConfig écrit une config dans un dossier temporaire et renvoie son chemin
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// loadProblems charge la config et renvoie les problèmes de la *ValidationError
+func loadProblems(t *testing.T, path string) []ConfigProblem {
+	t.Helper()
+	_, err := LoadConfig(path)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("erreur %v, attendu une *ValidationError", err)
+	}
+	if validationErr.Path != path {
+		t.Errorf("path = %q, attendu %q", validationErr.Path, path)
+	}
+	return validationErr.Problems
+}
+
+func checkProblems(t *testing.T, got, want []ConfigProblem) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%d problème(s) %+v, attendu %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i].Field != want[i].Field || got[i].Line != want[i].Line {
+			t.Errorf("problème %d = %s, attendu ligne %d, %s", i, got[i], want[i].Line, want[i].Field)
+		}
+		if want[i].Message != "" && got[i].Message != want[i].Message {
+			t.Errorf("problème %d: message %q, attendu %q", i, got[i].Message, want[i].Message)
+		}
+	}
+}
+
+const jsonConfig = `{
+  "settings": {
+    "workers": 4,
+    "timeout": "30s"
+  },
+  "logs": [
+    {
+      "id": "web",
+      "path": "/var/log/nginx/access.log",
+      "type": "nginx-access"
+    },
+    {"id": "app", "path": "app.log", "type": "custom-app"}
+  ],
+  "rules": [
+    {
+      "name": "erreurs",
+      "when": "errors > 10"
+    }
+  ]
+}`
+
+const yamlConfig = `settings:
+  workers: 4
+  timeout: 30s
+logs:
+  - id: web
+    path: /var/log/nginx/access.log
+    type: nginx-access
+  - id: app
+    path: app.log
+    type: custom-app
+rules:
+  - name: erreurs
+    when: errors > 10
+`
+
+func TestConfigLines(t *testing.T) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlConfig), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		lines map[string]int
+		want  map[string]int
+	}{
+		{
+			name:  "json",
+			lines: jsonLines([]byte(jsonConfig)),
+			want: map[string]int{
+				"settings": 2, "settings.workers": 3, "settings.timeout": 4,
+				"logs": 6, "logs[0]": 7, "logs[0].id": 8, "logs[0].type": 10,
+				"logs[1]": 12, "logs[1].path": 12,
+				"rules": 14, "rules[0]": 15, "rules[0].when": 17,
+			},
+		},
+		{
+			name:  "json liste de logs",
+			lines: jsonLines([]byte("[\n  {\"id\": \"a\"},\n  {\n    \"id\": \"b\"\n  }\n]")),
+			want:  map[string]int{"logs[0]": 2, "logs[0].id": 2, "logs[1]": 3, "logs[1].id": 4},
+		},
+		{
+			name: "toml",
+			lines: tomlLines([]byte(`workers = 1 # clé hors table, ignorée
+
+[settings]
+  workers = 4
+  "timeout" = "30s"
+
+[[logs]] # web
+id = "web"
+type = "nginx-access"
+
+[logs.format]
+pattern = "x"
+
+[[logs]]
+path = "app.log"
+
+[[rules]]
+when = "errors > 10"
+`)),
+			want: map[string]int{
+				"settings": 3, "settings.workers": 4, "settings.timeout": 5,
+				"logs": 7, "logs[0]": 7, "logs[0].id": 8, "logs[0].type": 9,
+				"logs[1]": 14, "logs[1].path": 15,
+				"rules": 17, "rules[0]": 17, "rules[0].when": 18,
+			},
+		},
+		{
+			name:  "yaml",
+			lines: yamlLines(&root),
+			want: map[string]int{
+				"settings": 1, "settings.workers": 2, "settings.timeout": 3,
+				"logs": 4, "logs[0]": 5, "logs[0].id": 5, "logs[0].type": 7,
+				"logs[1]": 8, "logs[1].path": 9,
+				"rules": 11, "rules[0]": 12, "rules[0].when": 13,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for field, line := range tt.want {
+				if got, ok := tt.lines[field]; !ok || got != line {
+					t.Errorf("%s: ligne %d (trouvé: %v), attendu %d", field, got, ok, line)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigReportsAllProblems(t *testing.T) {
+	path := writeConfig(t, "config.json", `{
+  "settings": {"workers": -1},
+  "logs": [
+    {"id": "a", "path": "a.log", "type": "inconnu"},
+    {
+      "id": "a",
+      "type": "regex"
+    }
+  ]
+}`)
+
+	checkProblems(t, loadProblems(t, path), []ConfigProblem{
+		{Field: "settings.workers", Line: 2},
+		{Field: "logs[0].type", Line: 4},
+		{Field: "logs[1].id", Line: 6},
+		{Field: "logs[1].path", Line: 5}, // champ absent: ligne de l'entrée
+		{Field: "logs[1].format", Line: 5},
+	})
+}
+
+func TestDurationErrorLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []ConfigProblem
+	}{
+		{
+			name: "json",
+			file: "config.json",
+			content: `{
+  "logs": [
+    {
+      "id": "app", "path": "app.log", "type": "generic",
+      "timeout": "abc"
+    }
+  ]
+}`,
+			want: []ConfigProblem{{Field: "logs[0].timeout", Line: 5, Message: `durée invalide "abc"`}},
+		},
+		{
+			name: "json settings et logs",
+			file: "config.json",
+			content: `{
+  "settings": {"timeout": true},
+  "logs": [{"id": "app", "path": "app.log", "type": "generic", "timeout": "10x"}]
+}`,
+			want: []ConfigProblem{
+				{Field: "settings.timeout", Line: 2, Message: "durée invalide true"},
+				{Field: "logs[0].timeout", Line: 3},
+			},
+		},
+		{
+			name:    "json liste de logs",
+			file:    "config.json",
+			content: "[\n  {\"id\": \"a\", \"path\": \"a.log\", \"type\": \"generic\"},\n  {\"id\": \"b\", \"path\": \"b.log\", \"type\": \"generic\", \"timeout\": \"abc\"}\n]",
+			want:    []ConfigProblem{{Field: "logs[1].timeout", Line: 3}},
+		},
+		{
+			name:    "yaml",
+			file:    "config.yaml",
+			content: "logs:\n  - id: app\n    path: app.log\n    type: generic\n    timeout: abc\n",
+			want:    []ConfigProblem{{Field: "logs[0].timeout", Line: 5, Message: `durée invalide "abc"`}},
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `[settings]
+timeout = "1x"
+
+[[logs]]
+id = "app"
+path = "app.log"
+type = "generic"
+timeout = "abc"
+`,
+			want: []ConfigProblem{
+				{Field: "settings.timeout", Line: 2},
+				{Field: "logs[0].timeout", Line: 8, Message: `durée invalide "abc"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkProblems(t, loadProblems(t, writeConfig(t, tt.file, tt.content)), tt.want)
+		})
+	}
+}
+
+// Les timeouts illisibles n'empêchent pas de signaler les autres problèmes
+func TestDurationAndValidationProblems(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "json",
+			file: "config.json",
+			content: `{
+  "settings": {"workers": -1, "timeout": "abc"},
+  "logs": [
+    {"id": "a", "path": "a.log", "type": "inconnu", "timeout": "1x"},
+    {"id": "a", "path": "b.log", "type": "generic"}
+  ]
+}`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `[settings]
+workers = -1
+timeout = "abc"
+
+[[logs]]
+id = "a"
+path = "a.log"
+type = "inconnu"
+timeout = "1x"
+
+[[logs]]
+id = "a"
+path = "b.log"
+type = "generic"
+`,
+		},
+	}
+	want := map[string][]ConfigProblem{
+		"json": {
+			{Field: "settings.timeout", Line: 2},
+			{Field: "logs[0].timeout", Line: 4},
+			{Field: "settings.workers", Line: 2},
+			{Field: "logs[0].type", Line: 4},
+			{Field: "logs[1].id", Line: 5},
+		},
+		"toml": {
+			{Field: "settings.timeout", Line: 3},
+			{Field: "logs[0].timeout", Line: 9},
+			{Field: "settings.workers", Line: 2},
+			{Field: "logs[0].type", Line: 8},
+			{Field: "logs[1].id", Line: 12},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkProblems(t, loadProblems(t, writeConfig(t, tt.file, tt.content)), want[tt.name])
+		})
+	}
+}
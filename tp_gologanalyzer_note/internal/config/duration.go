@@ -0,0 +1,45 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration accepte "30s", "1m30s"... ou un nombre de secondes dans le JSON
+type Duration time.Duration
+
+// Erreur de durée: le décodeur JSON ne dit pas quel champ l'a renvoyée,
+// decodeConfig la retrouve pour ajouter le champ et la ligne
+type DurationError struct {
+	Value string
+}
+
+func (e *DurationError) Error() string {
+	return fmt.Sprintf("durée invalide %s", e.Value)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		*d = Duration(v * float64(time.Second))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return &DurationError{Value: fmt.Sprintf("%q", v)}
+		}
+		*d = Duration(parsed)
+	default:
+		return &DurationError{Value: string(data)}
+	}
+	return nil
+}
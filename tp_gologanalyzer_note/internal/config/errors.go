@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Problème trouvé dans la config: champ concerné et ligne si connue
+type ConfigProblem struct {
+	Field   string
+	Line    int
+	Message string
+}
+
+func (p ConfigProblem) String() string {
+	switch {
+	case p.Line > 0 && p.Field != "":
+		return fmt.Sprintf("ligne %d, %s: %s", p.Line, p.Field, p.Message)
+	case p.Field != "":
+		return fmt.Sprintf("%s: %s", p.Field, p.Message)
+	}
+	return p.Message
+}
+
+// Erreur de validation: tous les problèmes de la config, pas seulement le premier
+type ValidationError struct {
+	Path     string
+	Problems []ConfigProblem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "config %s invalide (%d problème(s)):", e.Path, len(e.Problems))
+	for _, problem := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(problem.String())
+	}
+	return b.String()
+}
+
+// Constructeur
+func NewValidationError(path string, problems []ConfigProblem) *ValidationError {
+	return &ValidationError{
+		Path:     path,
+		Problems: problems,
+	}
+}
+
+// Helper pour vérifier le type d'erreur
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExpandPaths remplace les entrées dont le chemin est un glob ou un dossier
+// par une entrée par fichier trouvé. SourceID garde l'ID de l'entrée d'origine.
+// Une entrée sans correspondance est gardée telle quelle (l'analyse signalera l'erreur).
+func ExpandPaths(configs []LogConfig) ([]LogConfig, error) {
+	var expanded []LogConfig
+	paths := make(map[string]string)
+
+	for i, config := range configs {
+		files, err := matchFiles(config)
+		if err != nil {
+			return nil, fmt.Errorf("config %d (%s): %w", i, config.ID, err)
+		}
+		if files == nil {
+			files = []matchedFile{{path: config.Path}}
+		}
+
+		for _, file := range files {
+			unit := config
+			unit.Path = file.path
+			if file.name != "" {
+				unit.ID = config.ID + ":" + filepath.ToSlash(file.name)
+				unit.SourceID = config.ID
+			}
+			// Les IDs servent de clé partout (état, baseline, diff, métriques)
+			if other, exists := paths[unit.ID]; exists {
+				return nil, fmt.Errorf("config %d (%s): ID %q en double après développement (%s et %s)",
+					i, config.ID, unit.ID, other, unit.Path)
+			}
+			paths[unit.ID] = unit.Path
+			expanded = append(expanded, unit)
+		}
+	}
+
+	return expanded, nil
+}
+
+// Fichier trouvé et son nom relatif à la partie fixe du glob (ou au dossier)
+type matchedFile struct {
+	path string
+	name string
+}
+
+// matchFiles renvoie nil si le chemin n'est ni un glob ni un dossier
+func matchFiles(config LogConfig) ([]matchedFile, error) {
+	for _, pattern := range append(append([]string{}, config.Include...), config.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("motif invalide %q", pattern)
+		}
+	}
+
+	var roots []string
+	base := config.Path
+	if isGlob(config.Path) {
+		matches, err := filepath.Glob(config.Path)
+		if err != nil {
+			return nil, fmt.Errorf("glob invalide %q", config.Path)
+		}
+		roots = matches
+		base = globBase(config.Path)
+	} else if info, err := os.Stat(config.Path); err == nil && info.IsDir() {
+		roots = []string{config.Path}
+	} else {
+		return nil, nil
+	}
+
+	var files []matchedFile
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			if keepFile(config, filepath.Base(root)) {
+				files = append(files, matchedFile{path: root, name: relativeName(base, root)})
+			}
+			continue
+		}
+
+		dirFiles, err := walkDir(config, base, root)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dirFiles...)
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// walkDir liste les fichiers d'un dossier, sous-dossiers si Recursive;
+// les noms sont relatifs à base
+func walkDir(config LogConfig, base, root string) ([]matchedFile, error) {
+	var files []matchedFile
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && !config.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !keepFile(config, entry.Name()) {
+			return nil
+		}
+
+		files = append(files, matchedFile{path: path, name: relativeName(base, path)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lecture du dossier %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// keepFile applique les motifs include/exclude sur le nom du fichier
+func keepFile(config LogConfig, name string) bool {
+	for _, pattern := range config.Exclude {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(config.Include) == 0 {
+		return true
+	}
+	for _, pattern := range config.Include {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// globBase renvoie les dossiers du glob avant le premier joker:
+// "logs/*/app-*.log" => "logs"
+func globBase(pattern string) string {
+	base := filepath.Dir(pattern)
+	for isGlob(base) {
+		base = filepath.Dir(base)
+	}
+	return base
+}
+
+// relativeName: chemin relatif à base, le chemin complet si impossible
+func relativeName(base, path string) string {
+	name, err := filepath.Rel(base, path)
+	if err != nil || strings.HasPrefix(name, "..") {
+		return path
+	}
+	return name
+}
+
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// logTree crée les fichiers (chemins relatifs) et renvoie le dossier racine
+func logTree(t *testing.T, files ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestExpandPaths(t *testing.T) {
+	root := logTree(t,
+		"web/access.log", "web/access.log.1", "web/access.log.2.gz", "web/error.log",
+		"api/app.log", "api/old/app.log",
+	)
+
+	tests := []struct {
+		name   string
+		config LogConfig
+		want   []string // ID => chemin relatif à root
+	}{
+		{
+			name:   "fichier simple",
+			config: LogConfig{ID: "app", Path: "api/app.log"},
+			want:   []string{"app => api/app.log"},
+		},
+		{
+			name:   "glob",
+			config: LogConfig{ID: "web", Path: "web/access.log*"},
+			want: []string{
+				"web:access.log => web/access.log", "web:access.log.1 => web/access.log.1",
+				"web:access.log.2.gz => web/access.log.2.gz",
+			},
+		},
+		{
+			name:   "joker dans le dossier",
+			config: LogConfig{ID: "apps", Path: "*/app.log"},
+			want:   []string{"apps:api/app.log => api/app.log"},
+		},
+		{
+			name:   "dossier non récursif",
+			config: LogConfig{ID: "api", Path: "api"},
+			want:   []string{"api:app.log => api/app.log"},
+		},
+		{
+			name:   "dossier récursif",
+			config: LogConfig{ID: "api", Path: "api", Recursive: true},
+			want:   []string{"api:app.log => api/app.log", "api:old/app.log => api/old/app.log"},
+		},
+		{
+			name:   "include",
+			config: LogConfig{ID: "web", Path: "web", Include: []string{"*.log"}},
+			want:   []string{"web:access.log => web/access.log", "web:error.log => web/error.log"},
+		},
+		{
+			name:   "exclude prioritaire sur include",
+			config: LogConfig{ID: "web", Path: "web", Include: []string{"access.*"}, Exclude: []string{"*.gz"}},
+			want:   []string{"web:access.log => web/access.log", "web:access.log.1 => web/access.log.1"},
+		},
+		{
+			name:   "exclude seul",
+			config: LogConfig{ID: "web", Path: "web/*", Exclude: []string{"access*"}},
+			want:   []string{"web:error.log => web/error.log"},
+		},
+		{
+			name:   "tout exclu: entrée gardée telle quelle",
+			config: LogConfig{ID: "web", Path: "web", Exclude: []string{"*"}},
+			want:   []string{"web => web"},
+		},
+		{
+			name:   "glob sans correspondance",
+			config: LogConfig{ID: "db", Path: "db/*.log"},
+			want:   []string{"db => db/*.log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.Path = filepath.Join(root, filepath.FromSlash(config.Path))
+			expanded, err := ExpandPaths([]LogConfig{config})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, unit := range expanded {
+				rel, _ := filepath.Rel(root, unit.Path)
+				got = append(got, unit.ID+" => "+filepath.ToSlash(rel))
+				if unit.ID != config.ID && unit.SourceID != config.ID {
+					t.Errorf("%s: source %q, attendu %q", unit.ID, unit.SourceID, config.ID)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("développé en\n%s\nattendu\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestExpandPathsErrors(t *testing.T) {
+	root := logTree(t, "a/x.log", "b/x.log")
+
+	tests := []struct {
+		name    string
+		configs []LogConfig
+		want    string
+	}{
+		{
+			name:    "motif invalide",
+			configs: []LogConfig{{ID: "a", Path: filepath.Join(root, "a"), Include: []string{"["}}},
+			want:    `motif invalide "["`,
+		},
+		{
+			name: "ID en double après développement",
+			configs: []LogConfig{
+				{ID: "logs", Path: filepath.Join(root, "*", "x.log")},
+				{ID: "logs:a/x.log", Path: filepath.Join(root, "a", "x.log")},
+			},
+			want: `ID "logs:a/x.log" en double`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExpandPaths(tt.configs)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("erreur %v, attendu %q", err, tt.want)
+			}
+		})
+	}
+}
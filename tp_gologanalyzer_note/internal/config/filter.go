@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Filter sélectionne une partie des logs de la config.
+// Les valeurs d'un même critère sont des "ou", les critères entre eux des "et".
+type Filter struct {
+	IDs   []string `json:"ids,omitempty"` // globs sur l'ID ou l'ID d'origine
+	Tags  []string `json:"tags,omitempty"`
+	Types []string `json:"types,omitempty"`
+}
+
+// IsEmpty indique qu'aucun critère n'est donné
+func (f Filter) IsEmpty() bool {
+	return len(f.IDs) == 0 && len(f.Tags) == 0 && len(f.Types) == 0
+}
+
+// Validate vérifie les globs et les types
+func (f Filter) Validate() error {
+	for _, pattern := range f.IDs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("glob invalide %q", pattern)
+		}
+	}
+	for _, logType := range f.Types {
+		if !isKnownType(logType) {
+			return fmt.Errorf("type inconnu %q (types supportés: %s)", logType, strings.Join(KnownTypes(), ", "))
+		}
+	}
+	return nil
+}
+
+// Match indique si l'entrée passe le filtre
+func (f Filter) Match(config LogConfig) bool {
+	if len(f.IDs) > 0 && !matchAny(f.IDs, config.ID) &&
+		(config.SourceID == "" || !matchAny(f.IDs, config.SourceID)) {
+		return false
+	}
+	if len(f.Types) > 0 && !contains(f.Types, config.Type) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		for _, tag := range config.Tags {
+			if contains(f.Tags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Apply garde les entrées qui passent le filtre, dans l'ordre de la config
+func (f Filter) Apply(configs []LogConfig) []LogConfig {
+	if f.IsEmpty() {
+		return configs
+	}
+	var kept []LogConfig
+	for _, config := range configs {
+		if f.Match(config) {
+			kept = append(kept, config)
+		}
+	}
+	return kept
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterApply(t *testing.T) {
+	configs := []LogConfig{
+		{ID: "web-1", Type: TypeNginxAccess, Tags: []string{"prod", "front"}},
+		{ID: "web-2", Type: TypeNginxCombined, Tags: []string{"staging"}},
+		{ID: "api:app.log", SourceID: "api", Type: TypeJSONLines, Tags: []string{"prod"}},
+		{ID: "db", Type: TypeMySQLError},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{name: "vide", filter: Filter{}, want: "web-1,web-2,api:app.log,db"},
+		{name: "ID exact", filter: Filter{IDs: []string{"db"}}, want: "db"},
+		{name: "glob d'ID", filter: Filter{IDs: []string{"web-*"}}, want: "web-1,web-2"},
+		{name: "ID d'origine", filter: Filter{IDs: []string{"api"}}, want: "api:app.log"},
+		{name: "IDs en ou", filter: Filter{IDs: []string{"db", "web-2"}}, want: "web-2,db"},
+		{name: "tags en ou", filter: Filter{Tags: []string{"front", "staging"}}, want: "web-1,web-2"},
+		{name: "sans tag", filter: Filter{Tags: []string{"prod"}}, want: "web-1,api:app.log"},
+		{name: "types", filter: Filter{Types: []string{TypeNginxAccess, TypeMySQLError}}, want: "web-1,db"},
+		{name: "ID et tag", filter: Filter{IDs: []string{"web-*"}, Tags: []string{"prod"}}, want: "web-1"},
+		{name: "tag et type", filter: Filter{Tags: []string{"prod"}, Types: []string{TypeJSONLines}}, want: "api:app.log"},
+		{name: "critères incompatibles", filter: Filter{IDs: []string{"db"}, Tags: []string{"prod"}}, want: ""},
+		{name: "aucune correspondance", filter: Filter{IDs: []string{"cache"}}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, config := range tt.filter.Apply(configs) {
+				got = append(got, config.ID)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("gardés %v, attendu %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr string
+	}{
+		{name: "valide", filter: Filter{IDs: []string{"web-*"}, Types: []string{TypeSyslog}}},
+		{name: "glob invalide", filter: Filter{IDs: []string{"web-["}}, wantErr: `glob invalide "web-["`},
+		{name: "type inconnu", filter: Filter{Types: []string{"apache"}}, wantErr: `type inconnu "apache"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("erreur %v, attendu aucune", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("erreur %v, attendu %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Type des logs dont le format est décrit dans la config
+const TypeRegex = "regex"
+
+// Format d'un log de type "regex", ex:
+// { "pattern": "^%{TIMESTAMP_ISO8601:time} %{LOGLEVEL:level} %{GREEDYDATA:message}$" }
+type LogFormat struct {
+	// Regex avec groupes nommés (?P<champ>...) et/ou motifs %{NOM:champ}
+	Pattern string `json:"pattern"`
+
+	// Groupe de la date et son layout Go (défaut: formats ISO usuels)
+	TimeField  string `json:"time_field,omitempty"`
+	TimeLayout string `json:"time_layout,omitempty"`
+
+	// Groupe du niveau et correspondance valeur brute => niveau (ex: "E": "ERROR")
+	LevelField string            `json:"level_field,omitempty"`
+	Levels     map[string]string `json:"levels,omitempty"`
+
+	// Groupe du message (défaut: la ligne entière)
+	MessageField string `json:"message_field,omitempty"`
+
+	// Regex compilée au chargement
+	Regex *regexp.Regexp `json:"-"`
+}
+
+// Motifs utilisables avec %{NOM} ou %{NOM:champ}
+var grokPatterns = map[string]string{
+	"WORD":              `\w+`,
+	"NOTSPACE":          `\S+`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"INT":               `[+-]?\d+`,
+	"NUMBER":            `[+-]?\d+(?:\.\d+)?`,
+	"IP":                `(?:\d{1,3}\.){3}\d{1,3}|[0-9A-Fa-f:]*:[0-9A-Fa-f:.]+`,
+	"HOSTNAME":          `[A-Za-z0-9][A-Za-z0-9.-]*`,
+	"UUID":              `[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"`,
+	"LOGLEVEL":          `(?i:trace|debug|info|notice|warn(?:ing)?|err(?:or)?|crit(?:ical)?|fatal|alert|emerg(?:ency)?|panic)`,
+	"TIMESTAMP_ISO8601": `\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`,
+	"HTTPDATE":          `\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`,
+	"SYSLOGTIMESTAMP":   `[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}`,
+}
+
+// Ex: %{IP:client} ou %{GREEDYDATA}
+var grokRegex = regexp.MustCompile(`%\{(\w+)(?::(\w+))?\}`)
+
+// Niveaux acceptés dans "levels"
+var formatLevels = map[string]bool{"DEBUG": true, "INFO": true, "WARN": true, "ERROR": true, "FATAL": true}
+
+// GrokPatterns liste les motifs %{NOM} disponibles, triés
+func GrokPatterns() []string {
+	names := make([]string, 0, len(grokPatterns))
+	for name := range grokPatterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExpandPattern remplace les motifs %{NOM:champ} par des groupes nommés
+func ExpandPattern(pattern string) (string, error) {
+	var unknown []string
+	expanded := grokRegex.ReplaceAllStringFunc(pattern, func(match string) string {
+		m := grokRegex.FindStringSubmatch(match)
+		regex, ok := grokPatterns[m[1]]
+		if !ok {
+			unknown = append(unknown, m[1])
+			return match
+		}
+		if m[2] == "" {
+			return "(?:" + regex + ")"
+		}
+		return "(?P<" + m[2] + ">" + regex + ")"
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("motif inconnu %%{%s} (motifs: %s)", unknown[0], strings.Join(GrokPatterns(), ", "))
+	}
+	return expanded, nil
+}
+
+// validateFormat compile le motif et vérifie que les champs désignés existent
+func (v *validator) validateFormat(field string, format *LogFormat) {
+	if format.Pattern == "" {
+		v.addf(field+".pattern", "motif manquant")
+		return
+	}
+	expanded, err := ExpandPattern(format.Pattern)
+	if err != nil {
+		v.addf(field+".pattern", "%v", err)
+		return
+	}
+	regex, err := regexp.Compile(expanded)
+	if err != nil {
+		v.addf(field+".pattern", "regex invalide: %v", err)
+		return
+	}
+
+	groups := make(map[string]bool)
+	for _, name := range regex.SubexpNames() {
+		if name != "" {
+			groups[name] = true
+		}
+	}
+	if len(groups) == 0 {
+		v.addf(field+".pattern", "aucun groupe nommé: utiliser (?P<champ>...) ou %%{NOM:champ}")
+		return
+	}
+
+	ok := true
+	for _, ref := range []struct{ key, name string }{
+		{"time_field", format.TimeField},
+		{"level_field", format.LevelField},
+		{"message_field", format.MessageField},
+	} {
+		if ref.name != "" && !groups[ref.name] {
+			v.addf(field+"."+ref.key, "groupe %q absent du motif", ref.name)
+			ok = false
+		}
+	}
+	if format.TimeLayout != "" && format.TimeField == "" && !groups["time"] {
+		v.addf(field+".time_layout", "time_layout sans groupe de date (time_field ou groupe \"time\")")
+		ok = false
+	}
+	for raw, level := range format.Levels {
+		if !formatLevels[strings.ToUpper(level)] {
+			v.addf(field+".levels", "niveau inconnu %q pour %q (DEBUG, INFO, WARN, ERROR, FATAL)", level, raw)
+			ok = false
+		}
+	}
+	if ok {
+		format.Regex = regex
+	}
+}
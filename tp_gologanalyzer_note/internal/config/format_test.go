@@ -0,0 +1,117 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestExpandPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{name: "sans motif", pattern: `^(?P<msg>.*)$`, want: `^(?P<msg>.*)$`},
+		{name: "motif nommé", pattern: `%{INT:pid}`, want: `(?P<pid>[+-]?\d+)`},
+		{name: "motif anonyme", pattern: `%{WORD} x`, want: `(?:\w+) x`},
+		{
+			name:    "plusieurs motifs",
+			pattern: `^%{IP:client} %{NOTSPACE}$`,
+			want:    `^(?P<client>` + grokPatterns["IP"] + `) (?:\S+)$`,
+		},
+		{name: "motif inconnu", pattern: `%{INT:pid} %{NOPE:x}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandPattern(tt.pattern)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("aucune erreur, motif %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("motif %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Chaque motif doit reconnaître ses exemples en entier
+func TestGrokPatternsMatch(t *testing.T) {
+	tests := map[string][]string{
+		"TIMESTAMP_ISO8601": {
+			"2023-10-10T14:00:00Z", "2023-10-10 14:00:00", "2023-10-10T14:00:00.123+02:00",
+			"2023-10-10T14:00:00+0000", "2023-10-10 14:00:00,123",
+		},
+		"LOGLEVEL":        {"INFO", "warning", "Err", "CRITICAL"},
+		"IP":              {"192.168.1.1", "::1", "fe80::1"},
+		"HTTPDATE":        {"10/Oct/2023:14:00:00 +0000"},
+		"SYSLOGTIMESTAMP": {"Oct 11 22:14:15", "Oct  2 08:00:00"},
+		"QUOTEDSTRING":    {`"a \"b\" c"`},
+		"UUID":            {"123e4567-e89b-12d3-a456-426614174000"},
+	}
+
+	for name, values := range tests {
+		regex := regexp.MustCompile(`^(?:` + grokPatterns[name] + `)$`)
+		for _, value := range values {
+			if !regex.MatchString(value) {
+				t.Errorf("%%{%s} ne reconnaît pas %q", name, value)
+			}
+		}
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format LogFormat
+		fields []string // champs des problèmes attendus
+	}{
+		{name: "valide", format: LogFormat{Pattern: `^%{TIMESTAMP_ISO8601:time} %{GREEDYDATA:message}$`}},
+		{name: "motif manquant", format: LogFormat{}, fields: []string{"f.pattern"}},
+		{name: "motif inconnu", format: LogFormat{Pattern: `%{NOPE:x}`}, fields: []string{"f.pattern"}},
+		{name: "regex invalide", format: LogFormat{Pattern: `(?P<x>`}, fields: []string{"f.pattern"}},
+		{name: "aucun groupe nommé", format: LogFormat{Pattern: `%{WORD} .*`}, fields: []string{"f.pattern"}},
+		{
+			name:   "groupes désignés absents",
+			format: LogFormat{Pattern: `%{GREEDYDATA:msg}`, TimeField: "when", LevelField: "sev", MessageField: "text"},
+			fields: []string{"f.time_field", "f.level_field", "f.message_field"},
+		},
+		{
+			name:   "time_layout sans groupe de date",
+			format: LogFormat{Pattern: `%{GREEDYDATA:message}`, TimeLayout: "Jan 2"},
+			fields: []string{"f.time_layout"},
+		},
+		{
+			name:   "niveau inconnu",
+			format: LogFormat{Pattern: `%{WORD:level}`, Levels: map[string]string{"E": "GRAVE"}},
+			fields: []string{"f.levels"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{}
+			format := tt.format
+			v.validateFormat("f", &format)
+
+			if len(v.problems) != len(tt.fields) {
+				t.Fatalf("problèmes %+v, attendu %v", v.problems, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if v.problems[i].Field != field {
+					t.Errorf("problème %d sur %s, attendu %s", i, v.problems[i].Field, field)
+				}
+			}
+			if (format.Regex != nil) != (len(tt.fields) == 0) {
+				t.Errorf("regex compilée: %v, attendu %v", format.Regex != nil, len(tt.fields) == 0)
+			}
+		})
+	}
+}
@@ -1,13 +1,77 @@
 package config
 
 import (
-	"encoding/json"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"sort"
+	"sync"
 )
 
-// LoadConfig charge le fichier de config JSON
-func LoadConfig(configPath string) ([]LogConfig, error) {
+// Types de logs fournis avec l'outil. Ils sont déclarés ici pour que LoadConfig
+// les accepte sans dépendre de l'init de l'analyzer, qui leur associe un parser.
+const (
+	TypeNginxAccess   = "nginx-access"
+	TypeNginxCombined = "nginx-combined"
+	TypeApacheCommon  = "apache-common"
+	TypeMySQLError    = "mysql-error"
+	TypeSyslog        = "syslog"
+	TypeJSONLines     = "json-lines"
+	TypeCustomApp     = "custom-app"
+	TypeGeneric       = "generic"
+)
+
+// Types de logs acceptés: les types fournis, "regex" (décrit dans la config
+// elle-même) et ceux ajoutés par analyzer.Register
+var knownTypes = map[string]bool{
+	TypeRegex:         true,
+	TypeNginxAccess:   true,
+	TypeNginxCombined: true,
+	TypeApacheCommon:  true,
+	TypeMySQLError:    true,
+	TypeSyslog:        true,
+	TypeJSONLines:     true,
+	TypeCustomApp:     true,
+	TypeGeneric:       true,
+}
+
+// knownTypesMu protège knownTypes: un type peut être enregistré pendant
+// qu'une config est validée
+var knownTypesMu sync.RWMutex
+
+// RegisterType déclare un type de log valide en plus des types fournis
+func RegisterType(logType string) {
+	knownTypesMu.Lock()
+	defer knownTypesMu.Unlock()
+	knownTypes[logType] = true
+}
+
+// isKnownType indique si le type est accepté dans la config
+func isKnownType(logType string) bool {
+	knownTypesMu.RLock()
+	defer knownTypesMu.RUnlock()
+	return knownTypes[logType]
+}
+
+// KnownTypes liste les types valides, triés
+func KnownTypes() []string {
+	knownTypesMu.RLock()
+	defer knownTypesMu.RUnlock()
+
+	types := make([]string, 0, len(knownTypes))
+	for logType := range knownTypes {
+		types = append(types, logType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// LoadConfig charge le fichier de config (JSON, YAML ou TOML selon l'extension):
+// soit une liste de logs, soit un objet {"settings", "logs", "rules"}.
+// Les erreurs de validation sont toutes renvoyées dans un *ValidationError.
+func LoadConfig(configPath string) (*Config, error) {
 	// Vérif si le fichier existe
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("fichier config introuvable: %s", configPath)
@@ -20,33 +84,49 @@ func LoadConfig(configPath string) ([]LogConfig, error) {
 	}
 
 	// Fichier vide ?
+	data = bytes.TrimSpace(data)
 	if len(data) == 0 {
 		return nil, fmt.Errorf("fichier config vide")
 	}
 
-	// Parsing JSON
-	var configs []LogConfig
-	if err := json.Unmarshal(data, &configs); err != nil {
-		return nil, fmt.Errorf("erreur parsing JSON: %w", err)
+	// Parsing selon le format
+	cfg, lines, problems, err := decodeConfig(data, configFormat(configPath))
+	if err != nil {
+		return nil, err
 	}
 
-	// Au moins une config ?
-	if len(configs) == 0 {
-		return nil, fmt.Errorf("aucune config trouvée")
+	// Validation complète, à la suite des timeouts illisibles
+	v := &validator{lines: lines, problems: problems}
+	v.validateConfig(&cfg)
+	if len(v.problems) > 0 {
+		return nil, NewValidationError(configPath, v.problems)
 	}
 
-	// Validation des champs obligatoires
-	for i, config := range configs {
-		if config.ID == "" {
-			return nil, fmt.Errorf("config %d: ID manquant", i)
-		}
-		if config.Path == "" {
-			return nil, fmt.Errorf("config %d: chemin manquant", i)
-		}
-		if config.Type == "" {
-			return nil, fmt.Errorf("config %d: type manquant", i)
+	// Entrées désactivées
+	active := cfg.Logs[:0]
+	for _, config := range cfg.Logs {
+		if config.IsEnabled() {
+			active = append(active, config)
 		}
 	}
+	if len(active) == 0 {
+		return nil, fmt.Errorf("aucun log actif (toutes les entrées ont enabled: false)")
+	}
 
-	return configs, nil
-}
\ No newline at end of file
+	// Globs et dossiers => une entrée par fichier
+	cfg.Logs, err = ExpandPaths(active)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// FileHash renvoie l'empreinte sha256 du fichier de config ("sha256:...")
+func FileHash(configPath string) (string, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
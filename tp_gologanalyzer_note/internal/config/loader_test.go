@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Les types fournis sont acceptés sans importer l'analyzer, qui n'est pas lié ici
+func TestLoadConfigBuiltinTypes(t *testing.T) {
+	path := writeConfig(t, "config.yaml", `logs:
+  - id: web
+    path: access.log
+    type: nginx-access
+  - id: sys
+    path: syslog
+    type: syslog
+  - id: app
+    path: app.log
+    type: json-lines
+`)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Logs) != 3 {
+		t.Fatalf("%d logs, attendu 3", len(cfg.Logs))
+	}
+
+	path = writeConfig(t, "config.json", `[{"id": "a", "path": "a.log", "type": "nginx"}]`)
+	checkProblems(t, loadProblems(t, path), []ConfigProblem{{Field: "logs[0].type", Line: 1}})
+}
+
+// Un type enregistré pendant qu'une config est validée (à vérifier avec -race)
+func TestRegisterTypeConcurrent(t *testing.T) {
+	path := writeConfig(t, "config.json", `[{"id": "a", "path": "a.log", "type": "generic"}]`)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterType(fmt.Sprintf("test-concurrent-%d", i))
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := LoadConfig(path); err != nil {
+				t.Error(err)
+			}
+			KnownTypes()
+		}()
+	}
+	wg.Wait()
+
+	filter := Filter{Types: []string{"test-concurrent-3"}}
+	if err := filter.Validate(); err != nil {
+		t.Errorf("type enregistré refusé: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Règle d'alerte depuis la config, ex:
+// { "name": "web-erreurs", "logs": "web-*", "condition": "errors > 10" }
+type AlertRule struct {
+	Name      string `json:"name"`
+	Logs      string `json:"logs,omitempty"` // glob sur l'ID du log (défaut: tous)
+	Condition string `json:"condition"`
+
+	// Condition analysée au chargement
+	Parsed Condition `json:"-"`
+}
+
+// Condition est une comparaison "métrique opérateur seuil"
+type Condition struct {
+	Metric    string
+	Operator  string
+	Threshold float64 // ratio en fraction (2% => 0.02), durée en secondes
+}
+
+// Métriques utilisables dans les conditions et leur unité
+const (
+	unitCount    = "count"
+	unitRatio    = "ratio"
+	unitDuration = "duration"
+)
+
+var ruleMetrics = map[string]string{
+	"lines":          unitCount,
+	"parsed":         unitCount,
+	"unparsed":       unitCount,
+	"unparsed_ratio": unitRatio,
+	"warnings":       unitCount,
+	"errors":         unitCount,
+	"fatal":          unitCount,
+	"error_ratio":    unitRatio,
+	"requests":       unitCount,
+	"4xx":            unitCount,
+	"5xx":            unitCount,
+	"4xx_ratio":      unitRatio,
+	"5xx_ratio":      unitRatio,
+	"silence":        unitDuration,
+}
+
+// Ex: errors > 10 / 5xx_ratio >= 2% / silence > 1h
+var conditionRegex = regexp.MustCompile(`^\s*([a-z0-9_]+)\s*(>=|<=|==|!=|>|<)\s*(\S+)\s*$`)
+
+// ParseCondition analyse et valide une condition de règle
+func ParseCondition(text string) (Condition, error) {
+	m := conditionRegex.FindStringSubmatch(text)
+	if m == nil {
+		return Condition{}, fmt.Errorf("condition invalide %q (attendu: métrique opérateur seuil)", text)
+	}
+
+	unit, ok := ruleMetrics[m[1]]
+	if !ok {
+		return Condition{}, fmt.Errorf("métrique inconnue %q (métriques: %s)", m[1], strings.Join(RuleMetrics(), ", "))
+	}
+
+	condition := Condition{Metric: m[1], Operator: m[2]}
+	value := m[3]
+	var err error
+	switch unit {
+	case unitDuration:
+		var duration time.Duration
+		duration, err = time.ParseDuration(value)
+		condition.Threshold = duration.Seconds()
+	case unitRatio:
+		if strings.HasSuffix(value, "%") {
+			condition.Threshold, err = strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+			condition.Threshold /= 100
+		} else {
+			condition.Threshold, err = strconv.ParseFloat(value, 64)
+		}
+	default:
+		condition.Threshold, err = strconv.ParseFloat(value, 64)
+	}
+	if err != nil {
+		return Condition{}, fmt.Errorf("seuil invalide %q pour %s", value, m[1])
+	}
+	return condition, nil
+}
+
+// RuleMetrics liste les métriques disponibles, triées
+func RuleMetrics() []string {
+	metrics := make([]string, 0, len(ruleMetrics))
+	for metric := range ruleMetrics {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+// IsDuration indique si la métrique est une durée (seuil en secondes)
+func (c Condition) IsDuration() bool {
+	return ruleMetrics[c.Metric] == unitDuration
+}
+
+// IsRatio indique si la métrique est un ratio (seuil en fraction)
+func (c Condition) IsRatio() bool {
+	return ruleMetrics[c.Metric] == unitRatio
+}
+
+// Compare applique l'opérateur à une valeur mesurée
+func (c Condition) Compare(value float64) bool {
+	switch c.Operator {
+	case ">":
+		return value > c.Threshold
+	case ">=":
+		return value >= c.Threshold
+	case "<":
+		return value < c.Threshold
+	case "<=":
+		return value <= c.Threshold
+	case "==":
+		return value == c.Threshold
+	case "!=":
+		return value != c.Threshold
+	}
+	return false
+}
+
+// Matches indique si la règle s'applique au log (ID ou ID d'origine)
+func (r AlertRule) Matches(logID, sourceID string) bool {
+	if r.Logs == "" || r.Logs == "*" {
+		return true
+	}
+	if ok, _ := path.Match(r.Logs, logID); ok {
+		return true
+	}
+	if sourceID != "" {
+		ok, _ := path.Match(r.Logs, sourceID)
+		return ok
+	}
+	return false
+}
+
+// validateRules vérifie les règles et analyse leurs conditions
+func (v *validator) validateRules(rules []AlertRule) {
+	names := make(map[string]bool, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		field := fmt.Sprintf("rules[%d]", i)
+
+		if rule.Name == "" {
+			v.addf(field+".name", "nom manquant")
+		} else if names[rule.Name] {
+			v.addf(field+".name", "nom %q déjà utilisé", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if _, err := path.Match(rule.Logs, ""); err != nil {
+			v.addf(field+".logs", "glob invalide %q", rule.Logs)
+		}
+		condition, err := ParseCondition(rule.Condition)
+		if err != nil {
+			v.addf(field+".condition", "%v", err)
+			continue
+		}
+		rule.Parsed = condition
+	}
+}
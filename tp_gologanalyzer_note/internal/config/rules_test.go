@@ -0,0 +1,117 @@
+package config
+
+import "testing"
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Condition
+		wantErr bool
+	}{
+		{text: "errors > 10", want: Condition{Metric: "errors", Operator: ">", Threshold: 10}},
+		{text: "  lines>=0  ", want: Condition{Metric: "lines", Operator: ">=", Threshold: 0}},
+		{text: "fatal == 1", want: Condition{Metric: "fatal", Operator: "==", Threshold: 1}},
+		{text: "warnings != 2.5", want: Condition{Metric: "warnings", Operator: "!=", Threshold: 2.5}},
+		{text: "requests < 100", want: Condition{Metric: "requests", Operator: "<", Threshold: 100}},
+		{text: "5xx <= 3", want: Condition{Metric: "5xx", Operator: "<=", Threshold: 3}},
+		{text: "5xx_ratio >= 2%", want: Condition{Metric: "5xx_ratio", Operator: ">=", Threshold: 0.02}},
+		{text: "error_ratio > 0.5", want: Condition{Metric: "error_ratio", Operator: ">", Threshold: 0.5}},
+		{text: "silence > 1h30m", want: Condition{Metric: "silence", Operator: ">", Threshold: 5400}},
+		{text: "errors", wantErr: true},
+		{text: "errors => 10", wantErr: true},
+		{text: "cpu > 10", wantErr: true},
+		{text: "errors > dix", wantErr: true},
+		{text: "errors > 10%", wantErr: true},
+		{text: "4xx_ratio > x%", wantErr: true},
+		{text: "silence > 10", wantErr: true}, // durée sans unité
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseCondition(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("aucune erreur, condition %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("condition %+v, attendu %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Chaque opérateur autour du seuil: juste en dessous, égal, juste au-dessus
+func TestConditionCompare(t *testing.T) {
+	tests := []struct {
+		operator string
+		want     [3]bool // 9, 10, 11 pour un seuil de 10
+	}{
+		{operator: ">", want: [3]bool{false, false, true}},
+		{operator: ">=", want: [3]bool{false, true, true}},
+		{operator: "<", want: [3]bool{true, false, false}},
+		{operator: "<=", want: [3]bool{true, true, false}},
+		{operator: "==", want: [3]bool{false, true, false}},
+		{operator: "!=", want: [3]bool{true, false, true}},
+		{operator: "~", want: [3]bool{false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			condition := Condition{Metric: "errors", Operator: tt.operator, Threshold: 10}
+			for i, value := range []float64{9, 10, 11} {
+				if got := condition.Compare(value); got != tt.want[i] {
+					t.Errorf("%v %s 10 = %v, attendu %v", value, tt.operator, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAlertRuleMatches(t *testing.T) {
+	tests := []struct {
+		logs     string
+		logID    string
+		sourceID string
+		want     bool
+	}{
+		{logs: "", logID: "web", want: true},
+		{logs: "*", logID: "web", want: true},
+		{logs: "web-*", logID: "web-front", want: true},
+		{logs: "web-*", logID: "api", want: false},
+		{logs: "web-*", logID: "web-front#2", sourceID: "web-front", want: true}, // glob étendu
+		{logs: "api", logID: "api-0", sourceID: "db", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.logs+"/"+tt.logID, func(t *testing.T) {
+			rule := AlertRule{Name: "r", Logs: tt.logs}
+			if got := rule.Matches(tt.logID, tt.sourceID); got != tt.want {
+				t.Errorf("Matches(%q, %q) = %v, attendu %v", tt.logID, tt.sourceID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	path := writeConfig(t, "config.json", `{
+  "logs": [{"id": "a", "path": "a.log", "type": "generic"}],
+  "rules": [
+    {"name": "ok", "condition": "errors > 1"},
+    {"name": "ok", "condition": "errors > 1"},
+    {"condition": "cpu > 1"},
+    {"name": "glob", "logs": "[", "condition": "lines > 1"}
+  ]
+}`)
+
+	checkProblems(t, loadProblems(t, path), []ConfigProblem{
+		{Field: "rules[1].name", Line: 5},
+		{Field: "rules[2].name", Line: 6},
+		{Field: "rules[2].condition", Line: 6},
+		{Field: "rules[3].logs", Line: 7},
+	})
+}
@@ -1,23 +1,254 @@
 package config
 
+import "time"
+
+// Config complète: réglages globaux, logs à analyser, règles d'alerte
+// et corrélation entre fichiers
+type Config struct {
+	Settings    Settings     `json:"settings"`
+	Logs        []LogConfig  `json:"logs"`
+	Rules       []AlertRule  `json:"rules,omitempty"`
+	Correlation *Correlation `json:"correlation,omitempty"`
+}
+
+// Réglages globaux, les flags de la ligne de commande sont prioritaires
+type Settings struct {
+	Workers int      `json:"workers,omitempty"`
+	Timeout Duration `json:"timeout,omitempty"` // délai par défaut des fichiers
+	Output  string   `json:"output,omitempty"`
+	Format  string   `json:"format,omitempty"`
+}
+
 // Config d'un fichier de log depuis le JSON
 type LogConfig struct {
 	ID   string `json:"id"`
 	Path string `json:"path"`
 	Type string `json:"type"`
+
+	// Format du log si Type vaut "regex"
+	Format *LogFormat `json:"format,omitempty"`
+
+	// Durée max d'analyse du fichier, ex: "30s" (optionnel)
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// Si Path est un glob ou un dossier (optionnels)
+	Recursive bool     `json:"recursive,omitempty"`
+	Include   []string `json:"include,omitempty"`
+	Exclude   []string `json:"exclude,omitempty"`
+
+	// Regrouper les messages par motif (nombres, IP... masqués) (optionnel)
+	Cluster bool `json:"cluster,omitempty"`
+
+	// Histogramme d'activité: "minute", "hour" ou "day" (optionnel)
+	Timeline string `json:"timeline,omitempty"`
+
+	// Lignes triées par date: --since peut sauter directement au bon endroit (optionnel)
+	Sorted bool `json:"sorted,omitempty"`
+
+	// Étiquettes libres (optionnel)
+	Tags []string `json:"tags,omitempty"`
+
+	// false pour désactiver l'entrée sans la supprimer (défaut: true)
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// ID de l'entrée d'origine quand Path a été développé
+	SourceID string `json:"-"`
+}
+
+// IsEnabled indique si l'entrée doit être analysée
+func (c LogConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
 }
 
 // Résultat après analyse d'un log
 type AnalysisResult struct {
 	LogID        string `json:"log_id"`
+	SourceID     string `json:"source_id,omitempty"`
 	FilePath     string `json:"file_path"`
 	Status       string `json:"status"`
 	Message      string `json:"message"`
 	ErrorDetails string `json:"error_details"`
+	ErrorCode    string `json:"error_code,omitempty"`
+
+	// Durée d'analyse du fichier
+	DurationMs int64 `json:"duration_ms"`
+
+	// Statistiques de parsing
+	TotalLines    int      `json:"total_lines"`
+	ParsedLines   int      `json:"parsed_lines"`
+	UnparsedLines int      `json:"unparsed_lines"`
+	ParseErrors   []string `json:"parse_errors,omitempty"`
+
+	// Fenêtre --since/--until: lignes parsées hors fenêtre, lignes sans date
+	// et octets sautés par la recherche dans un log trié
+	OutOfWindow  int   `json:"out_of_window,omitempty"`
+	Undated      int   `json:"undated,omitempty"`
+	SkippedBytes int64 `json:"skipped_bytes,omitempty"`
+	// Log trié lu en partie (saut au début de la fenêtre ou arrêt après la fin):
+	// total_lines, unparsed_lines, out_of_window et undated ne comptent que les
+	// lignes lues. parsed_lines et les statistiques restent exactes.
+	PartialCounts bool `json:"partial_counts,omitempty"`
+
+	// Compression détectée (none, gzip, bzip2, zstd) et tailles
+	Compression      string `json:"compression,omitempty"`
+	CompressedSize   int64  `json:"compressed_size,omitempty"`
+	UncompressedSize int64  `json:"uncompressed_size,omitempty"`
+
+	// Analyse incrémentale: reprise depuis un checkpoint
+	Resumed     bool  `json:"resumed,omitempty"`
+	ResumedFrom int64 `json:"resumed_from,omitempty"`
+	NewLines    int   `json:"new_lines,omitempty"`
+
+	// Statistiques par niveau de sévérité
+	Levels    *LevelCounts `json:"levels,omitempty"`
+	FirstSeen *time.Time   `json:"first_seen,omitempty"`
+	LastSeen  *time.Time   `json:"last_seen,omitempty"`
+	TopErrors []TopEntry   `json:"top_errors,omitempty"`
+
+	// Motifs de messages les plus fréquents (si le regroupement est activé)
+	Clusters          []Cluster `json:"clusters,omitempty"`
+	DistinctTemplates int       `json:"distinct_templates,omitempty"`
+
+	// Lignes et erreurs par tranche de temps (si l'histogramme est activé)
+	Timeline *Timeline `json:"timeline,omitempty"`
+
+	// Statistiques de trafic (access logs HTTP)
+	Access *AccessStats `json:"access,omitempty"`
+
+	// Écarts par rapport à la baseline (analyze --baseline)
+	Anomalies []Anomaly `json:"anomalies,omitempty"`
+}
+
+// Synthèse du trafic d'un access log
+type AccessStats struct {
+	Requests      int               `json:"requests"`
+	StatusClasses StatusClassCounts `json:"status_classes"`
+	StatusCodes   map[string]int    `json:"status_codes"`
+	Methods       map[string]int    `json:"methods"`
+	TopPaths      []TopEntry        `json:"top_paths"`
+	TopClients    []TopEntry        `json:"top_clients"`
+	BytesServed   int64             `json:"bytes_served"`
+}
+
+// Répartition des codes HTTP par classe
+type StatusClassCounts struct {
+	Informational int `json:"1xx"`
+	Success       int `json:"2xx"`
+	Redirection   int `json:"3xx"`
+	ClientError   int `json:"4xx"`
+	ServerError   int `json:"5xx"`
+}
+
+// Histogramme d'activité: Lines[i] et Errors[i] couvrent
+// [Start + i*bucket, Start + (i+1)*bucket[, tranches alignées en UTC
+type Timeline struct {
+	Bucket string    `json:"bucket"`
+	Start  time.Time `json:"start"`
+	Lines  []int     `json:"lines"`
+	Errors []int     `json:"errors"`
+
+	// Trop de tranches: seules les dernières sont gardées
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Tailles de tranche possibles pour l'histogramme
+var timelineBuckets = map[string]time.Duration{
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+}
+
+// TimelineBucket renvoie la durée d'une tranche ("minute", "hour", "day")
+func TimelineBucket(name string) (time.Duration, bool) {
+	duration, ok := timelineBuckets[name]
+	return duration, ok
+}
+
+// Motif de message: parties variables remplacées par <NUM>, <IP>...
+type Cluster struct {
+	Template string `json:"template"`
+	Count    int    `json:"count"`
+	Level    string `json:"level,omitempty"` // niveau le plus grave vu
+	Sample   string `json:"sample"`          // premier message rencontré
+}
+
+// Nombre de lignes par niveau
+type LevelCounts struct {
+	Debug int `json:"debug"`
+	Info  int `json:"info"`
+	Warn  int `json:"warn"`
+	Error int `json:"error"`
+	Fatal int `json:"fatal"`
+}
+
+// Valeur la plus fréquente et son nombre d'occurrences
+type TopEntry struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Résultat d'une recherche (commande search) dans un log
+type SearchResult struct {
+	LogID        string        `json:"log_id"`
+	SourceID     string        `json:"source_id,omitempty"`
+	FilePath     string        `json:"file_path"`
+	Status       string        `json:"status"`
+	ErrorDetails string        `json:"error_details,omitempty"`
+	ErrorCode    string        `json:"error_code,omitempty"`
+	LinesScanned int           `json:"lines_scanned"`
+	Matches      []SearchMatch `json:"matches"`
+
+	// Limite --max-count atteinte, la suite du fichier n'a pas été lue
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Ligne trouvée, avec ses lignes de contexte
+type SearchMatch struct {
+	Line   int           `json:"line"`
+	Text   string        `json:"text"`
+	Before []ContextLine `json:"before,omitempty"`
+	After  []ContextLine `json:"after,omitempty"`
+}
+
+// Ligne de contexte autour d'une correspondance
+type ContextLine struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// Alerte déclenchée par une règle sur un log
+type Alert struct {
+	Rule      string `json:"rule"`
+	LogID     string `json:"log_id"`
+	Condition string `json:"condition"`
+	Value     string `json:"value"`
+	Message   string `json:"message"`
+}
+
+// Écart d'une métrique par rapport à la baseline, en écarts-types
+type Anomaly struct {
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Expected  float64 `json:"expected"`
+	StdDev    float64 `json:"stddev"`
+	Deviation float64 `json:"deviation"`
+	Message   string  `json:"message"`
 }
 
 // Status possibles
 const (
-	StatusOK     = "OK"
-	StatusFailed = "FAILED"
-)
\ No newline at end of file
+	StatusOK      = "OK"
+	StatusFailed  = "FAILED"
+	StatusTimeout = "TIMEOUT"
+)
+
+// Codes d'erreur (error_code), stables pour les scripts
+const (
+	ErrorCodeNotFound         = "not_found"
+	ErrorCodePermissionDenied = "permission_denied"
+	ErrorCodeIsDirectory      = "is_directory"
+	ErrorCodeParse            = "parse_error"
+	ErrorCodeTimeout          = "timeout"
+	ErrorCodeInterrupted      = "interrupted"
+	ErrorCodeIO               = "io_error"
+)
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// validator accumule les problèmes au lieu de s'arrêter au premier
+type validator struct {
+	lines    map[string]int
+	problems []ConfigProblem
+}
+
+// addf ajoute un problème; la ligne est celle du champ, sinon celle de l'entrée
+func (v *validator) addf(field string, format string, args ...any) {
+	line, ok := v.lines[field]
+	if !ok {
+		if i := strings.LastIndexByte(field, '.'); i > 0 {
+			line = v.lines[field[:i]]
+		}
+	}
+	v.problems = append(v.problems, ConfigProblem{
+		Field:   field,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validateConfig vérifie toute la config et développe les variables d'environnement
+func (v *validator) validateConfig(cfg *Config) {
+	settings := &cfg.Settings
+	if settings.Workers < 0 {
+		v.addf("settings.workers", "doit être positif")
+	}
+	if settings.Timeout < 0 {
+		v.addf("settings.timeout", "timeout négatif")
+	}
+	settings.Output = v.expandEnv("settings.output", settings.Output)
+
+	if len(cfg.Logs) == 0 {
+		v.addf("logs", "aucune config trouvée")
+	}
+
+	ids := make(map[string]int, len(cfg.Logs))
+	for i := range cfg.Logs {
+		config := &cfg.Logs[i]
+		field := fmt.Sprintf("logs[%d]", i)
+
+		if config.ID == "" {
+			v.addf(field+".id", "ID manquant")
+		} else if first, exists := ids[config.ID]; exists {
+			v.addf(field+".id", "ID %q déjà utilisé par logs[%d]", config.ID, first)
+		} else {
+			ids[config.ID] = i
+		}
+
+		if config.Path == "" {
+			v.addf(field+".path", "chemin manquant")
+		}
+		config.Path = v.expandEnv(field+".path", config.Path)
+
+		if config.Type == "" {
+			v.addf(field+".type", "type manquant")
+		} else if !isKnownType(config.Type) {
+			v.addf(field+".type", "type inconnu %q (types supportés: %s)",
+				config.Type, strings.Join(KnownTypes(), ", "))
+		}
+		switch {
+		case config.Type == TypeRegex && config.Format == nil:
+			v.addf(field+".format", "format manquant pour le type %q", TypeRegex)
+		case config.Type == TypeRegex:
+			v.validateFormat(field+".format", config.Format)
+		case config.Format != nil:
+			v.addf(field+".format", "format n'est utilisé qu'avec le type %q", TypeRegex)
+		}
+		if config.Timeout < 0 {
+			v.addf(field+".timeout", "timeout négatif")
+		}
+		if _, ok := TimelineBucket(config.Timeline); config.Timeline != "" && !ok {
+			v.addf(field+".timeline", "tranche inconnue %q (minute, hour ou day)", config.Timeline)
+		}
+	}
+
+	v.validateRules(cfg.Rules)
+	if cfg.Correlation != nil {
+		v.validateCorrelation(cfg.Correlation)
+	}
+}
+
+// expandEnv remplace ${VAR} et $VAR; une variable non définie est un problème
+func (v *validator) expandEnv(field, value string) string {
+	return os.Expand(value, func(name string) string {
+		if name == "$" {
+			return "$" // "$$" => "$"
+		}
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			v.addf(field, "variable d'environnement %s non définie", name)
+		}
+		return env
+	})
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("LA_TEST_DIR", "/var/log/app")
+	t.Setenv("LA_TEST_EMPTY", "")
+
+	tests := []struct {
+		name     string
+		value    string
+		want     string
+		problems []string // variables signalées non définies
+	}{
+		{name: "accolades", value: "${LA_TEST_DIR}/app.log", want: "/var/log/app/app.log"},
+		{name: "sans accolades", value: "$LA_TEST_DIR/app.log", want: "/var/log/app/app.log"},
+		{name: "définie vide", value: "${LA_TEST_EMPTY}app.log", want: "app.log"},
+		{name: "dollar échappé", value: "cost$$.log", want: "cost$.log"},
+		{name: "sans variable", value: "/var/log/app.log", want: "/var/log/app.log"},
+		{name: "non définie", value: "${LA_TEST_UNSET}/app.log", want: "/app.log",
+			problems: []string{"LA_TEST_UNSET"}},
+		{name: "plusieurs non définies", value: "$LA_TEST_UNSET/${LA_TEST_UNSET2}/${LA_TEST_DIR}", want: "///var/log/app",
+			problems: []string{"LA_TEST_UNSET", "LA_TEST_UNSET2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{}
+			if got := v.expandEnv("logs[0].path", tt.value); got != tt.want {
+				t.Errorf("expandEnv(%q) = %q, attendu %q", tt.value, got, tt.want)
+			}
+			if len(v.problems) != len(tt.problems) {
+				t.Fatalf("problèmes %v, attendu %v non définies", v.problems, tt.problems)
+			}
+			for i, name := range tt.problems {
+				want := "variable d'environnement " + name + " non définie"
+				if v.problems[i].Field != "logs[0].path" || v.problems[i].Message != want {
+					t.Errorf("problème %s, attendu logs[0].path: %s", v.problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+// Les variables non définies sont signalées avec les autres problèmes, à la
+// ligne du champ
+func TestLoadConfigUnsetVariables(t *testing.T) {
+	t.Setenv("LA_TEST_DIR", t.TempDir())
+	path := writeConfig(t, "config.yaml", `settings:
+  output: ${LA_TEST_UNSET_OUT}/rapport.json
+logs:
+  - id: app
+    path: ${LA_TEST_DIR}/app.log
+    type: generic
+  - id: web
+    path: ${LA_TEST_UNSET_LOGS}/access.log
+    type: inconnu
+`)
+
+	problems := loadProblems(t, path)
+	checkProblems(t, problems, []ConfigProblem{
+		{Field: "settings.output", Line: 2, Message: "variable d'environnement LA_TEST_UNSET_OUT non définie"},
+		{Field: "logs[1].path", Line: 8, Message: "variable d'environnement LA_TEST_UNSET_LOGS non définie"},
+		{Field: "logs[1].type", Line: 9},
+	})
+	if !strings.Contains(problems[2].Message, "type inconnu") {
+		t.Errorf("message %q, attendu type inconnu", problems[2].Message)
+	}
+}
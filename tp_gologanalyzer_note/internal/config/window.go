@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeWindow limite l'analyse aux lignes datées entre Since et Until.
+// Une borne à zéro n'est pas appliquée.
+type TimeWindow struct {
+	Since time.Time `json:"since,omitzero"`
+	Until time.Time `json:"until,omitzero"`
+}
+
+// IsZero indique qu'aucune borne n'est donnée
+func (w TimeWindow) IsZero() bool {
+	return w.Since.IsZero() && w.Until.IsZero()
+}
+
+// Contains indique si la date est dans la fenêtre (bornes incluses)
+func (w TimeWindow) Contains(t time.Time) bool {
+	return !w.Before(t) && !w.After(t)
+}
+
+// Before indique si la date est avant le début de la fenêtre
+func (w TimeWindow) Before(t time.Time) bool {
+	return !w.Since.IsZero() && t.Before(w.Since)
+}
+
+// After indique si la date est après la fin de la fenêtre
+func (w TimeWindow) After(t time.Time) bool {
+	return !w.Until.IsZero() && t.After(w.Until)
+}
+
+// Formats de date acceptés pour --since / --until (heure locale si pas de fuseau)
+var windowLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseWindowBound lit une borne: date absolue (RFC 3339, "2006-01-02 15:04"...),
+// durée relative à now ("2h", "30m", "7d") ou "now"
+func ParseWindowBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if value == "now" {
+		return now, nil
+	}
+
+	// Durée relative, "il y a ..."
+	relative := strings.TrimPrefix(value, "-")
+	if days, ok := strings.CutSuffix(relative, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if duration, err := time.ParseDuration(relative); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range windowLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date invalide %q (ex: 2h, 7d, 2025-09-24, 2025-09-24T14:00:00Z)", value)
+}
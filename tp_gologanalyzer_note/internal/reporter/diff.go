@@ -0,0 +1,283 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Types de changement d'un log entre deux rapports
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeStatus    = "status"
+	ChangeModified  = "modified"
+	ChangeUnchanged = "unchanged"
+)
+
+// Taille lue dans le message des anciens rapports ("taille: 154 bytes")
+var legacySizeRegex = regexp.MustCompile(`taille: (\d+) bytes`)
+
+// LogDiff compare un log présent dans l'un ou l'autre des rapports
+type LogDiff struct {
+	LogID       string   `json:"log_id"`
+	Change      string   `json:"change"`
+	OldStatus   string   `json:"old_status,omitempty"`
+	NewStatus   string   `json:"new_status,omitempty"`
+	OldErrors   int      `json:"old_errors"`
+	NewErrors   int      `json:"new_errors"`
+	ErrorsDelta int      `json:"errors_delta"`
+	OldLines    int      `json:"old_lines"`
+	NewLines    int      `json:"new_lines"`
+	LinesDelta  int      `json:"lines_delta"`
+	OldSize     int64    `json:"old_size"`
+	NewSize     int64    `json:"new_size"`
+	SizeDelta   int64    `json:"size_delta"`
+	Regression  bool     `json:"regression"`
+	Reasons     []string `json:"reasons,omitempty"`
+
+	// Compteurs absents d'un des rapports (ancien format): erreurs, lignes et
+	// taille ne sont pas comparées
+	CountsUnknown bool `json:"counts_unknown,omitempty"`
+}
+
+// ReportDiff est le résultat de la comparaison de deux rapports
+type ReportDiff struct {
+	OldRunID    string `json:"old_run_id,omitempty"`
+	NewRunID    string `json:"new_run_id,omitempty"`
+	Added       int    `json:"added"`
+	Removed     int    `json:"removed"`
+	Changed     int    `json:"changed"`
+	Regressions int    `json:"regressions"`
+	// Logs comparés sans compteurs (CountsUnknown)
+	Unknown int       `json:"unknown,omitempty"`
+	Logs    []LogDiff `json:"logs"`
+}
+
+// LoadReport lit un rapport exporté en JSON: enveloppe versionnée
+// ou ancien format (tableau de résultats, ex: rapport_final.json)
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire le rapport: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var results []config.AnalysisResult
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil, fmt.Errorf("rapport %s invalide: %w", path, err)
+		}
+		return &Report{Results: results, Summary: Summarize(results)}, nil
+	}
+
+	var report Report
+	if err := json.Unmarshal(trimmed, &report); err != nil {
+		return nil, fmt.Errorf("rapport %s invalide: %w", path, err)
+	}
+	if report.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("rapport %s: schéma v%d non supporté (max v%d)", path, report.SchemaVersion, SchemaVersion)
+	}
+	return &report, nil
+}
+
+// CompareReports compare deux rapports log par log (clé = log_id).
+// Une régression est un status qui se dégrade ou des erreurs qui augmentent
+// de plus de errorTolerance.
+func CompareReports(oldReport, newReport *Report, errorTolerance int) *ReportDiff {
+	diff := &ReportDiff{OldRunID: oldReport.RunID, NewRunID: newReport.RunID}
+
+	oldByID := make(map[string]config.AnalysisResult, len(oldReport.Results))
+	for _, result := range oldReport.Results {
+		oldByID[result.LogID] = result
+	}
+	seen := make(map[string]bool, len(newReport.Results))
+
+	for _, newResult := range newReport.Results {
+		seen[newResult.LogID] = true
+		oldResult, existed := oldByID[newResult.LogID]
+
+		logDiff := LogDiff{
+			LogID:     newResult.LogID,
+			NewStatus: newResult.Status,
+			NewErrors: errorCount(newResult),
+			NewLines:  newResult.TotalLines,
+			NewSize:   resultSize(newResult),
+		}
+		if !existed {
+			logDiff.Change = ChangeAdded
+			diff.Added++
+		} else {
+			logDiff.OldStatus = oldResult.Status
+			logDiff.OldErrors = errorCount(oldResult)
+			logDiff.OldLines = oldResult.TotalLines
+			logDiff.OldSize = resultSize(oldResult)
+			logDiff.CountsUnknown = !countsKnown(oldReport, oldResult) || !countsKnown(newReport, newResult)
+			compareResults(&logDiff, errorTolerance)
+			if logDiff.Change != ChangeUnchanged {
+				diff.Changed++
+			}
+			if logDiff.CountsUnknown {
+				diff.Unknown++
+			}
+		}
+		fillDeltas(&logDiff)
+		if logDiff.Regression {
+			diff.Regressions++
+		}
+		diff.Logs = append(diff.Logs, logDiff)
+	}
+
+	// Logs disparus, dans l'ordre de l'ancien rapport
+	for _, oldResult := range oldReport.Results {
+		if seen[oldResult.LogID] {
+			continue
+		}
+		seen[oldResult.LogID] = true
+		logDiff := LogDiff{
+			LogID:     oldResult.LogID,
+			Change:    ChangeRemoved,
+			OldStatus: oldResult.Status,
+			OldErrors: errorCount(oldResult),
+			OldLines:  oldResult.TotalLines,
+			OldSize:   resultSize(oldResult),
+		}
+		fillDeltas(&logDiff)
+		diff.Removed++
+		diff.Logs = append(diff.Logs, logDiff)
+	}
+
+	return diff
+}
+
+// countsKnown: les anciens rapports (tableau sans enveloppe) n'ont ni levels ni
+// total_lines, un résultat OK sans levels non plus. Un échec compte 0 erreur.
+func countsKnown(report *Report, result config.AnalysisResult) bool {
+	if report.SchemaVersion == 0 {
+		return false
+	}
+	return result.Levels != nil || result.Status != config.StatusOK
+}
+
+// compareResults détermine le type de changement et les régressions. Sans
+// compteurs, seul le status est comparé.
+func compareResults(logDiff *LogDiff, errorTolerance int) {
+	logDiff.Change = ChangeUnchanged
+	if !logDiff.CountsUnknown && (logDiff.OldErrors != logDiff.NewErrors ||
+		logDiff.OldLines != logDiff.NewLines || logDiff.OldSize != logDiff.NewSize) {
+		logDiff.Change = ChangeModified
+	}
+
+	if logDiff.OldStatus != logDiff.NewStatus {
+		logDiff.Change = ChangeStatus
+		if statusRank(logDiff.NewStatus) > statusRank(logDiff.OldStatus) {
+			logDiff.Regression = true
+			logDiff.Reasons = append(logDiff.Reasons,
+				fmt.Sprintf("status %s -> %s", logDiff.OldStatus, logDiff.NewStatus))
+		}
+	}
+	if increase := logDiff.NewErrors - logDiff.OldErrors; !logDiff.CountsUnknown && increase > errorTolerance {
+		logDiff.Regression = true
+		logDiff.Reasons = append(logDiff.Reasons, fmt.Sprintf("+%d erreurs", increase))
+	}
+}
+
+func fillDeltas(logDiff *LogDiff) {
+	if logDiff.CountsUnknown {
+		return
+	}
+	logDiff.ErrorsDelta = logDiff.NewErrors - logDiff.OldErrors
+	logDiff.LinesDelta = logDiff.NewLines - logDiff.OldLines
+	logDiff.SizeDelta = logDiff.NewSize - logDiff.OldSize
+}
+
+// statusRank: plus c'est grand, plus c'est grave
+func statusRank(status string) int {
+	switch status {
+	case config.StatusOK:
+		return 0
+	case config.StatusTimeout:
+		return 1
+	case config.StatusFailed:
+		return 2
+	}
+	return 0
+}
+
+func errorCount(result config.AnalysisResult) int {
+	if result.Levels == nil {
+		return 0
+	}
+	return result.Levels.Error + result.Levels.Fatal
+}
+
+// resultSize renvoie la taille du fichier, y compris pour les anciens rapports
+func resultSize(result config.AnalysisResult) int64 {
+	if result.CompressedSize > 0 {
+		return result.CompressedSize
+	}
+	if m := legacySizeRegex.FindStringSubmatch(result.Message); m != nil {
+		size, _ := strconv.ParseInt(m[1], 10, 64)
+		return size
+	}
+	return 0
+}
+
+// WriteDiffJSON écrit la comparaison en JSON indenté
+func WriteDiffJSON(w io.Writer, diff *ReportDiff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(diff)
+}
+
+// PrintDiff affiche la comparaison sous forme de tableau (logs inchangés masqués
+// sauf si showAll)
+func PrintDiff(w io.Writer, diff *ReportDiff, showAll bool) {
+	logs := append([]LogDiff(nil), diff.Logs...)
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Regression && !logs[j].Regression })
+
+	fmt.Fprintf(w, "%-30s %-10s %-18s %-16s %-14s %s\n",
+		"LOG", "CHANGEMENT", "STATUS", "ERREURS", "TAILLE", "RÉGRESSION")
+	for _, logDiff := range logs {
+		if logDiff.Change == ChangeUnchanged && !showAll {
+			continue
+		}
+		status := logDiff.NewStatus
+		if logDiff.OldStatus != logDiff.NewStatus {
+			status = fmt.Sprintf("%s -> %s", dash(logDiff.OldStatus), dash(logDiff.NewStatus))
+		}
+		regression := ""
+		if logDiff.Regression {
+			regression = fmt.Sprint(logDiff.Reasons)
+		}
+		errors := fmt.Sprintf("%d (%+d)", logDiff.NewErrors, logDiff.ErrorsDelta)
+		size := fmt.Sprintf("%+d B", logDiff.SizeDelta)
+		if logDiff.CountsUnknown {
+			errors, size = "?", "?"
+		}
+		fmt.Fprintf(w, "%-30s %-10s %-18s %-16s %-14s %s\n",
+			logDiff.LogID, logDiff.Change, status, errors, size, regression)
+	}
+
+	fmt.Fprintf(w, "\nAjoutés: %d | Disparus: %d | Modifiés: %d | Régressions: %d\n",
+		diff.Added, diff.Removed, diff.Changed, diff.Regressions)
+	if diff.Unknown > 0 {
+		fmt.Fprintf(w, "%d log(s) comparé(s) à un rapport sans compteurs (ancien format): "+
+			"seul le status est comparé (\"?\")\n", diff.Unknown)
+	}
+}
+
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
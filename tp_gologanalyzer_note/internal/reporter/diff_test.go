@@ -0,0 +1,139 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// writeReport écrit value en JSON dans un dossier temporaire
+func writeReport(t *testing.T, name string, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func okResult(id string, errors, lines int, size int64) config.AnalysisResult {
+	return config.AnalysisResult{
+		LogID:          id,
+		Status:         config.StatusOK,
+		TotalLines:     lines,
+		CompressedSize: size,
+		Levels:         &config.LevelCounts{Info: lines - errors, Error: errors},
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+	failed := config.AnalysisResult{LogID: "app", Status: config.StatusFailed}
+	tests := []struct {
+		name      string
+		old, new  config.AnalysisResult
+		tolerance int
+
+		change     string
+		regression bool
+	}{
+		{name: "inchangé", old: okResult("app", 2, 10, 100), new: okResult("app", 2, 10, 100), change: ChangeUnchanged},
+		{name: "lignes ajoutées", old: okResult("app", 2, 10, 100), new: okResult("app", 2, 12, 140), change: ChangeModified},
+		{name: "erreurs en hausse", old: okResult("app", 2, 10, 100), new: okResult("app", 3, 11, 120),
+			change: ChangeModified, regression: true},
+		{name: "erreurs dans la tolérance", old: okResult("app", 2, 10, 100), new: okResult("app", 3, 11, 120),
+			tolerance: 1, change: ChangeModified},
+		{name: "erreurs en baisse", old: okResult("app", 5, 10, 100), new: okResult("app", 1, 10, 100), change: ChangeModified},
+		{name: "status dégradé", old: okResult("app", 0, 10, 100), new: failed, change: ChangeStatus, regression: true},
+		{name: "status rétabli", old: failed, new: okResult("app", 0, 10, 100), change: ChangeStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldReport := &Report{SchemaVersion: SchemaVersion, Results: []config.AnalysisResult{tt.old}}
+			newReport := &Report{SchemaVersion: SchemaVersion, Results: []config.AnalysisResult{tt.new}}
+			diff := CompareReports(oldReport, newReport, tt.tolerance)
+
+			logDiff := diff.Logs[0]
+			if logDiff.Change != tt.change || logDiff.Regression != tt.regression {
+				t.Errorf("changement %s, régression %v %v, attendu %s, %v",
+					logDiff.Change, logDiff.Regression, logDiff.Reasons, tt.change, tt.regression)
+			}
+			if logDiff.CountsUnknown {
+				t.Errorf("compteurs inconnus entre deux rapports v%d", SchemaVersion)
+			}
+		})
+	}
+}
+
+func TestCompareReportsAddedRemoved(t *testing.T) {
+	oldReport := &Report{SchemaVersion: SchemaVersion, Results: []config.AnalysisResult{
+		okResult("a", 0, 1, 10), okResult("b", 0, 1, 10),
+	}}
+	newReport := &Report{SchemaVersion: SchemaVersion, Results: []config.AnalysisResult{
+		okResult("b", 0, 1, 10), okResult("c", 0, 1, 10),
+	}}
+	diff := CompareReports(oldReport, newReport, 0)
+
+	var changes []string
+	for _, logDiff := range diff.Logs {
+		changes = append(changes, logDiff.LogID+":"+logDiff.Change)
+	}
+	if got := strings.Join(changes, " "); got != "b:unchanged c:added a:removed" {
+		t.Errorf("changements %s", got)
+	}
+	if diff.Added != 1 || diff.Removed != 1 || diff.Changed != 0 || diff.Regressions != 0 {
+		t.Errorf("bilan %+v", diff)
+	}
+}
+
+// Un ancien rapport (tableau sans levels ni total_lines) comparé au nouveau
+// rapport du même log: rien n'a changé, aucune régression
+func TestCompareLegacyReport(t *testing.T) {
+	oldPath := writeReport(t, "rapport_final.json", []map[string]string{
+		{"log_id": "app-backend-2", "file_path": "app.log", "status": "OK",
+			"message": "Analyse terminée avec succès (taille: 154 bytes)", "error_details": ""},
+		{"log_id": "invalid-path", "file_path": "/non/existent/log.log", "status": "FAILED",
+			"message": "Fichier introuvable", "error_details": "absent"},
+	})
+	newPath := writeReport(t, "rapport.json", &Report{
+		SchemaVersion: SchemaVersion,
+		Results: []config.AnalysisResult{
+			okResult("app-backend-2", 1, 4, 154),
+			{LogID: "invalid-path", Status: config.StatusFailed, ErrorCode: config.ErrorCodeNotFound},
+		},
+	})
+
+	oldReport, err := LoadReport(oldPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newReport, err := LoadReport(newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := CompareReports(oldReport, newReport, 0)
+
+	if diff.Regressions != 0 || diff.Changed != 0 || diff.Unknown != 2 {
+		t.Errorf("bilan %+v, attendu 0 régression, 0 modifié, 2 inconnus", diff)
+	}
+	for _, logDiff := range diff.Logs {
+		if !logDiff.CountsUnknown || logDiff.Change != ChangeUnchanged || logDiff.ErrorsDelta != 0 {
+			t.Errorf("%s: %+v", logDiff.LogID, logDiff)
+		}
+	}
+
+	var out bytes.Buffer
+	PrintDiff(&out, diff, true)
+	if !strings.Contains(out.String(), "2 log(s) comparé(s) à un rapport sans compteurs") {
+		t.Errorf("sortie sans avertissement:\n%s", out.String())
+	}
+}
@@ -0,0 +1,329 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Formats d'export
+const (
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatJUnit    = "junit"
+)
+
+// formatWriter écrit le rapport dans un format donné
+type formatWriter func(w io.Writer, report *Report) error
+
+var formatWriters = map[string]formatWriter{
+	FormatJSON:     writeJSON,
+	FormatNDJSON:   writeNDJSON,
+	FormatCSV:      writeCSV,
+	FormatMarkdown: writeMarkdown,
+	FormatHTML:     writeHTML,
+	FormatJUnit:    writeJUnit,
+}
+
+// Extensions reconnues pour deviner le format
+var formatExtensions = map[string]string{
+	".json":     FormatJSON,
+	".ndjson":   FormatNDJSON,
+	".jsonl":    FormatNDJSON,
+	".csv":      FormatCSV,
+	".md":       FormatMarkdown,
+	".markdown": FormatMarkdown,
+	".html":     FormatHTML,
+	".htm":      FormatHTML,
+	".xml":      FormatJUnit,
+}
+
+// Formats liste les formats d'export disponibles
+func Formats() []string {
+	formats := make([]string, 0, len(formatWriters))
+	for format := range formatWriters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// FormatFromPath déduit le format de l'extension (json par défaut)
+func FormatFromPath(path string) string {
+	if format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return FormatJSON
+}
+
+// IsFormat indique si le format est supporté
+func IsFormat(format string) bool {
+	_, ok := formatWriters[format]
+	return ok
+}
+
+// writeJSON écrit l'enveloppe complète (métadonnées + résultats)
+func writeJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
+
+// writeNDJSON écrit un résultat par ligne, sans enveloppe
+func writeNDJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, result := range report.Results {
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var csvHeader = []string{
+	"log_id", "source_id", "file_path", "status", "message", "error_details", "error_code",
+	"total_lines", "parsed_lines", "unparsed_lines",
+	"debug", "info", "warn", "error", "fatal", "first_seen", "last_seen",
+	"compression", "compressed_size", "uncompressed_size",
+	"requests", "status_2xx", "status_3xx", "status_4xx", "status_5xx", "bytes_served",
+}
+
+// writeCSV écrit une ligne par fichier avec les compteurs principaux
+func writeCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, result := range report.Results {
+		levels := levelsOf(result)
+		var access config.AccessStats
+		if result.Access != nil {
+			access = *result.Access
+		}
+
+		record := []string{
+			result.LogID, result.SourceID, result.FilePath, result.Status, result.Message, result.ErrorDetails, result.ErrorCode,
+			strconv.Itoa(result.TotalLines), strconv.Itoa(result.ParsedLines), strconv.Itoa(result.UnparsedLines),
+			strconv.Itoa(levels.Debug), strconv.Itoa(levels.Info), strconv.Itoa(levels.Warn),
+			strconv.Itoa(levels.Error), strconv.Itoa(levels.Fatal),
+			formatTime(result.FirstSeen), formatTime(result.LastSeen),
+			result.Compression, strconv.FormatInt(result.CompressedSize, 10), strconv.FormatInt(result.UncompressedSize, 10),
+			strconv.Itoa(access.Requests),
+			strconv.Itoa(access.StatusClasses.Success), strconv.Itoa(access.StatusClasses.Redirection),
+			strconv.Itoa(access.StatusClasses.ClientError), strconv.Itoa(access.StatusClasses.ServerError),
+			strconv.FormatInt(access.BytesServed, 10),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeMarkdown écrit un tableau récapitulatif puis le détail des erreurs
+func writeMarkdown(w io.Writer, report *Report) error {
+	var b strings.Builder
+	results := report.Results
+	summary := report.Summary
+
+	b.WriteString("# Rapport d'analyse des logs\n\n")
+	fmt.Fprintf(&b, "Run `%s` sur %s, %s (%d ms), loganalyzer %s, config `%s`\n\n",
+		report.RunID, report.Hostname, report.StartedAt.Format(time.RFC3339), report.DurationMs,
+		report.ToolVersion, report.ConfigPath)
+	fmt.Fprintf(&b, "- Fichiers analysés: %d\n- Succès: %d\n- Échecs: %d\n- Timeouts: %d\n\n",
+		summary.Total, summary.OK, summary.Failed, summary.Timeout)
+
+	if len(report.Alerts) > 0 {
+		b.WriteString("## Alertes\n\n| Règle | Log | Condition | Valeur |\n|-------|-----|-----------|-------:|\n")
+		for _, alert := range report.Alerts {
+			fmt.Fprintf(&b, "| %s | %s | `%s` | %s |\n",
+				markdownEscape(alert.Rule), markdownEscape(alert.LogID), markdownEscape(alert.Condition), alert.Value)
+		}
+		b.WriteString("\n")
+	}
+
+	if correlation := report.Correlation; correlation != nil {
+		fmt.Fprintf(&b, "## Corrélation (`%s`)\n\n%s\n\n", markdownEscape(correlation.Field), correlation.String())
+		if len(correlation.ResumedLogs) > 0 {
+			fmt.Fprintf(&b, "> Repris depuis un checkpoint (nouvelles lignes seulement, chaînes des runs précédents coupées): %s\n\n",
+				markdownEscape(strings.Join(correlation.ResumedLogs, ", ")))
+		}
+		if len(correlation.ErroredRequests) > 0 {
+			b.WriteString("| ID | Latence (ms) | Access log | Erreur |\n|----|-------------:|------------|--------|\n")
+			for _, request := range correlation.ErroredRequests {
+				access := "-"
+				if request.Access != nil {
+					access = request.Access.LogID + ": " + request.Access.Message
+				}
+				fmt.Fprintf(&b, "| %s | %d | %s | %s |\n",
+					markdownEscape(request.ID), request.LatencyMs, markdownEscape(access),
+					markdownEscape(request.Error.LogID+": "+request.Error.Message))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("| Log | Fichier | Status | Lignes | Rejetées | WARN | ERROR | FATAL |\n")
+	b.WriteString("|-----|---------|--------|-------:|---------:|-----:|------:|------:|\n")
+	for _, result := range results {
+		levels := levelsOf(result)
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %d | %d | %d |\n",
+			markdownEscape(result.LogID), markdownEscape(result.FilePath), result.Status,
+			result.TotalLines, result.UnparsedLines, levels.Warn, levels.Error, levels.Fatal)
+	}
+
+	for _, result := range results {
+		if result.ErrorDetails == "" && len(result.TopErrors) == 0 && len(result.Clusters) == 0 &&
+			result.Timeline == nil && result.Access == nil && len(result.Anomalies) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", markdownEscape(result.LogID), markdownEscape(result.Message))
+		if result.ErrorDetails != "" {
+			fmt.Fprintf(&b, "\n> Erreur: %s\n", markdownEscape(result.ErrorDetails))
+		}
+		for _, anomaly := range result.Anomalies {
+			fmt.Fprintf(&b, "\n> Anomalie: %s\n", markdownEscape(anomaly.Message))
+		}
+		if len(result.TopErrors) > 0 {
+			b.WriteString("\n| Erreur fréquente | Nombre |\n|---|---:|\n")
+			for _, entry := range result.TopErrors {
+				fmt.Fprintf(&b, "| %s | %d |\n", markdownEscape(entry.Value), entry.Count)
+			}
+		}
+		if timeline := result.Timeline; timeline != nil {
+			fmt.Fprintf(&b, "\nActivité par %s depuis %s:\n\n```\nlignes  |%s|\nerreurs |%s|\n```\n",
+				timeline.Bucket, timeline.Start.Format(time.RFC3339),
+				Sparkline(timeline.Lines, sparklineWidth), Sparkline(timeline.Errors, sparklineWidth))
+		}
+		if len(result.Clusters) > 0 {
+			fmt.Fprintf(&b, "\n| Motif (%d distincts) | Niveau | Nombre | Exemple |\n|---|---|---:|---|\n", result.DistinctTemplates)
+			for _, cluster := range result.Clusters {
+				fmt.Fprintf(&b, "| `%s` | %s | %d | %s |\n", markdownEscape(cluster.Template), cluster.Level,
+					cluster.Count, markdownEscape(cluster.Sample))
+			}
+		}
+		if access := result.Access; access != nil {
+			classes := access.StatusClasses
+			fmt.Fprintf(&b, "\nTrafic: %d requêtes, %d bytes - 2xx=%d 3xx=%d 4xx=%d 5xx=%d\n",
+				access.Requests, access.BytesServed,
+				classes.Success, classes.Redirection, classes.ClientError, classes.ServerError)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownEscape(text string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+}
+
+// Format JUnit XML: un testcase par fichier de log
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Hostname  string          `xml:"hostname,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit: FAILED devient un failure, TIMEOUT une error
+func writeJUnit(w io.Writer, report *Report) error {
+	suite := junitTestSuite{
+		Name:      "loganalyzer",
+		Tests:     len(report.Results),
+		Hostname:  report.Hostname,
+		Time:      float64(report.DurationMs) / 1000,
+		Timestamp: report.StartedAt.Format("2006-01-02T15:04:05"),
+	}
+
+	for _, result := range report.Results {
+		className := "loganalyzer"
+		if result.SourceID != "" {
+			className += "." + result.SourceID
+		}
+		testCase := junitTestCase{
+			Name:      result.LogID,
+			ClassName: className,
+			SystemOut: result.Message,
+		}
+
+		problem := &junitProblem{Message: result.Message, Type: result.Status, Text: result.ErrorDetails}
+		if result.ErrorCode != "" {
+			problem.Type = result.ErrorCode
+		}
+		switch result.Status {
+		case config.StatusFailed:
+			testCase.Failure = problem
+			suite.Failures++
+		case config.StatusTimeout:
+			testCase.Error = problem
+			suite.Errors++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func levelsOf(result config.AnalysisResult) config.LevelCounts {
+	if result.Levels == nil {
+		return config.LevelCounts{}
+	}
+	return *result.Levels
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
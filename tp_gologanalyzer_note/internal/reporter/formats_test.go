@@ -0,0 +1,251 @@
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Message avec tous les caractères à échapper selon le format
+const trickyMessage = "a,b \"cité\" | <script>alert('x')</script> & co\nligne 2"
+
+// testReport construit un rapport avec un succès, un échec et un timeout
+func testReport() *Report {
+	started := time.Date(2025, 9, 24, 14, 0, 0, 0, time.UTC)
+	lastSeen := started.Add(-time.Minute)
+	app := okResult("app", 2, 10, 154)
+	app.FilePath = "logs/app,1.log"
+	app.Message = trickyMessage
+	app.LastSeen = &lastSeen
+	app.TopErrors = []config.TopEntry{{Value: "boom | <b>", Count: 2}}
+	app.Clusters = []config.Cluster{{Template: "user <NUM> | ko", Count: 2, Level: "ERROR", Sample: "user 42 | ko"}}
+	app.Timeline = &config.Timeline{Bucket: "1m", Start: started, Lines: []int{1, 0, 9}, Errors: []int{0, 0, 2}}
+	app.Access = &config.AccessStats{Requests: 10, StatusClasses: config.StatusClassCounts{Success: 8, ServerError: 2}}
+
+	results := []config.AnalysisResult{
+		app,
+		{LogID: "absent", SourceID: "src", FilePath: "absent.log", Status: config.StatusFailed,
+			Message: "Fichier introuvable", ErrorDetails: `open "absent.log": <no such file>`, ErrorCode: config.ErrorCodeNotFound},
+		{LogID: "lent", FilePath: "nfs.log", Status: config.StatusTimeout, Message: "Timeout après 1s"},
+	}
+	alerts := []config.Alert{{Rule: "r|1", LogID: "app", Condition: "errors > 1", Value: "2", Message: "r|1: errors = 2"}}
+	return &Report{
+		SchemaVersion: SchemaVersion,
+		RunID:         "0123456789abcdef",
+		StartedAt:     started,
+		FinishedAt:    started.Add(1500 * time.Millisecond),
+		DurationMs:    1500,
+		Hostname:      "web-01",
+		ToolVersion:   "1.2.0",
+		ConfigPath:    "config.json",
+		Summary:       Summarize(results),
+		Alerts:        alerts,
+		Results:       results,
+		Correlation: &config.CorrelationReport{
+			Field: "request_id", Requests: 4, Joined: 3, Errored: 1, ResumedLogs: []string{"api", "nginx"},
+		},
+	}
+}
+
+func writeFormat(t *testing.T, format string, report *Report) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := formatWriters[format](&b, report); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]string{
+		"rapport.json": FormatJSON, "r.NDJSON": FormatNDJSON, "r.jsonl": FormatNDJSON,
+		"r.csv": FormatCSV, "r.md": FormatMarkdown, "r.markdown": FormatMarkdown,
+		"r.htm": FormatHTML, "r.html": FormatHTML, "r.xml": FormatJUnit,
+		"r.txt": FormatJSON, "rapport": FormatJSON,
+	}
+	for path, want := range tests {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, attendu %q", path, got, want)
+		}
+	}
+	for _, format := range Formats() {
+		if !IsFormat(format) {
+			t.Errorf("format %q listé mais refusé", format)
+		}
+	}
+}
+
+// Le JSON relu puis réécrit est identique: aucun champ perdu
+func TestJSONRoundTrip(t *testing.T) {
+	output := writeFormat(t, FormatJSON, testReport())
+
+	var decoded Report
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if again := writeFormat(t, FormatJSON, &decoded); again != output {
+		t.Errorf("JSON relu différent:\n%s\nattendu:\n%s", again, output)
+	}
+	if decoded.Results[0].Message != trickyMessage {
+		t.Errorf("message %q, attendu %q", decoded.Results[0].Message, trickyMessage)
+	}
+	if strings.Contains(output, `\u003c`) {
+		t.Error("< échappé en \\u003c, attendu tel quel")
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	report := testReport()
+	scanner := bufio.NewScanner(strings.NewReader(writeFormat(t, FormatNDJSON, report)))
+	var ids []string
+	for scanner.Scan() {
+		var result config.AnalysisResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("ligne %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, result.LogID)
+		if result.LogID == "app" && result.Message != trickyMessage {
+			t.Errorf("message %q, attendu %q", result.Message, trickyMessage)
+		}
+	}
+	if strings.Join(ids, ",") != "app,absent,lent" {
+		t.Errorf("résultats %v, attendu app, absent, lent", ids)
+	}
+}
+
+// Virgules, guillemets et retours à la ligne survivent à une relecture CSV
+func TestCSVRoundTrip(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(writeFormat(t, FormatCSV, testReport()))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("%d lignes, attendu en-tête + 3", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(csvHeader, ",") {
+		t.Errorf("en-tête %v, attendu %v", records[0], csvHeader)
+	}
+
+	column := make(map[string]int, len(csvHeader))
+	for i, name := range csvHeader {
+		column[name] = i
+	}
+	tests := []struct {
+		row   int
+		field string
+		want  string
+	}{
+		{1, "file_path", "logs/app,1.log"},
+		{1, "message", trickyMessage},
+		{1, "error", "2"},
+		{1, "last_seen", "2025-09-24T13:59:00Z"},
+		{1, "status_5xx", "2"},
+		{2, "source_id", "src"},
+		{2, "error_details", `open "absent.log": <no such file>`},
+		{2, "error_code", config.ErrorCodeNotFound},
+		{2, "total_lines", "0"},
+		{3, "status", config.StatusTimeout},
+	}
+	for _, tt := range tests {
+		if got := records[tt.row][column[tt.field]]; got != tt.want {
+			t.Errorf("ligne %d, %s = %q, attendu %q", tt.row, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	output := writeFormat(t, FormatMarkdown, testReport())
+
+	for _, want := range []string{
+		"Run `0123456789abcdef` sur web-01, 2025-09-24T14:00:00Z (1500 ms), loganalyzer 1.2.0, config `config.json`\n",
+		"- Fichiers analysés: 3\n- Succès: 1\n- Échecs: 1\n- Timeouts: 1\n",
+		"| r\\|1 | app | `errors > 1` | 2 |\n",
+		"| app | logs/app,1.log | OK | 10 | 0 | 0 | 2 | 0 |\n",
+		"| absent | absent.log | FAILED | 0 | 0 | 0 | 0 | 0 |\n",
+		"\n## app\n\na,b \"cité\" \\| <script>alert('x')</script> & co ligne 2\n",
+		"| boom \\| <b> | 2 |\n",
+		"| `user <NUM> \\| ko` | ERROR | 2 | user 42 \\| ko |\n",
+		"lignes  |▂ █|\nerreurs |  █|\n",
+		"\n> Erreur: open \"absent.log\": <no such file>\n",
+		"## Corrélation (`request_id`)\n\n4 requêtes (3 dans plusieurs logs), 1 en erreur (0 sans access log)\n\n" +
+			"> Repris depuis un checkpoint (nouvelles lignes seulement, chaînes des runs précédents coupées): api, nginx\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("markdown sans %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestHTMLEscaping(t *testing.T) {
+	output := writeFormat(t, FormatHTML, testReport())
+
+	for _, want := range []string{
+		"a,b &#34;cité&#34; | &lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt; &amp; co\nligne 2",
+		"<small>open &#34;absent.log&#34;: &lt;no such file&gt;</small>",
+		"<td>boom | &lt;b&gt;</td>",
+		"<code>user &lt;NUM&gt; | ko</code>",
+		`<td class="FAILED">FAILED</td>`,
+		"lignes  |▂ █|",
+		"chaînes des runs précédents coupées):\napi, nginx</small>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HTML sans %q", want)
+		}
+	}
+	if strings.Contains(output, "<script>") || strings.Contains(output, "<b>") {
+		t.Error("balise du message non échappée dans le HTML")
+	}
+}
+
+func TestJUnitRoundTrip(t *testing.T) {
+	output := writeFormat(t, FormatJUnit, testReport())
+	if !strings.HasPrefix(output, xml.Header) {
+		t.Errorf("en-tête XML manquant: %q", output[:min(len(output), 40)])
+	}
+
+	var decoded junitTestSuites
+	if err := xml.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Suites) != 1 {
+		t.Fatalf("%d suites, attendu 1", len(decoded.Suites))
+	}
+	suite := decoded.Suites[0]
+	if suite.Tests != 3 || suite.Failures != 1 || suite.Errors != 1 || suite.Time != 1.5 {
+		t.Errorf("suite %d tests, %d failures, %d errors, %vs, attendu 3, 1, 1, 1.5s",
+			suite.Tests, suite.Failures, suite.Errors, suite.Time)
+	}
+
+	app, absent, slow := suite.Cases[0], suite.Cases[1], suite.Cases[2]
+	if app.Failure != nil || app.Error != nil || app.SystemOut != trickyMessage {
+		t.Errorf("testcase app %+v, attendu succès avec le message en sortie", app)
+	}
+	if absent.ClassName != "loganalyzer.src" || absent.Failure == nil ||
+		absent.Failure.Type != config.ErrorCodeNotFound || absent.Failure.Text != `open "absent.log": <no such file>` {
+		t.Errorf("testcase absent %+v, attendu failure %s", absent, config.ErrorCodeNotFound)
+	}
+	if slow.Error == nil || slow.Error.Type != config.StatusTimeout || slow.Failure != nil {
+		t.Errorf("testcase lent %+v, attendu error %s", slow, config.StatusTimeout)
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	tests := map[string]string{
+		"a | b":            `a \| b`,
+		"ligne 1\nligne 2": "ligne 1 ligne 2",
+		"crlf\r\nsuite":    "crlf suite",
+		"cr\rseul":         "cr seul",
+	}
+	for text, want := range tests {
+		if got := markdownEscape(text); got != want {
+			t.Errorf("markdownEscape(%q) = %q, attendu %q", text, got, want)
+		}
+	}
+}
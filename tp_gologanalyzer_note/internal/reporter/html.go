@@ -0,0 +1,205 @@
+package reporter
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Segment d'une barre horizontale (largeur en %)
+type barSegment struct {
+	Label string
+	Count int
+	Width float64
+	Color string
+}
+
+// Ligne du rapport HTML
+type htmlRow struct {
+	Result  config.AnalysisResult
+	Levels  config.LevelCounts
+	Bar     []barSegment
+	Traffic []barSegment
+
+	// Sparklines de l'histogramme (vides sans timeline)
+	ActivityLine string
+	ErrorLine    string
+}
+
+type htmlReport struct {
+	*Report
+	StatusBar []barSegment
+	Rows      []htmlRow
+}
+
+// Couleurs des graphiques
+const (
+	colorOK      = "#2e7d32"
+	colorFailed  = "#c62828"
+	colorTimeout = "#ef6c00"
+	colorDebug   = "#90a4ae"
+	colorInfo    = "#1e88e5"
+	colorWarn    = "#fbc02d"
+	colorError   = "#e53935"
+	colorFatal   = "#6a1b9a"
+)
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="fr">
+<head>
+<meta charset="utf-8">
+<title>Rapport loganalyzer</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ddd; padding: 6px 8px; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+td.num { text-align: right; }
+.OK { color: #2e7d32; font-weight: bold; }
+.FAILED { color: #c62828; font-weight: bold; }
+.TIMEOUT { color: #ef6c00; font-weight: bold; }
+.bar { display: flex; height: 14px; width: 260px; background: #eee; }
+.bar div { height: 100%; }
+.legend span { display: inline-block; margin-right: 1em; }
+.legend i { display: inline-block; width: 10px; height: 10px; margin-right: 4px; }
+small { color: #666; }
+tr.alert td { background: #fdecea; }
+ul.anomalies li { color: #c62828; }
+pre.spark { font-size: 18px; line-height: 1.1; }
+</style>
+</head>
+<body>
+<h1>Rapport d'analyse des logs</h1>
+<p><small>Run {{.RunID}} sur {{.Hostname}} - {{.StartedAt.Format "2006-01-02 15:04:05"}} ({{.DurationMs}} ms)
+- loganalyzer {{.ToolVersion}} - config {{.ConfigPath}}{{if .Interrupted}} - <strong>interrompu</strong>{{end}}</small></p>
+
+<h2>Bilan</h2>
+<p>{{.Summary.Total}} fichiers - Succès: {{.Summary.OK}} | Échecs: {{.Summary.Failed}} | Timeouts: {{.Summary.Timeout}}</p>
+<div class="bar" style="width: 520px; height: 22px">{{range .StatusBar}}<div style="width: {{.Width}}%; background: {{.Color}}" title="{{.Label}}: {{.Count}}"></div>{{end}}</div>
+
+{{if .Alerts}}<h2>Alertes</h2>
+<table>
+<tr><th>Règle</th><th>Log</th><th>Condition</th><th>Valeur</th></tr>
+{{range .Alerts}}<tr class="alert"><td>{{.Rule}}</td><td>{{.LogID}}</td><td><code>{{.Condition}}</code></td><td class="num">{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+
+{{with .Correlation}}<h2>Corrélation ({{.Field}})</h2>
+<p>{{.String}}</p>
+{{if .ResumedLogs}}<p><small>Repris depuis un checkpoint (nouvelles lignes seulement, chaînes des runs précédents coupées):
+{{range $i, $log := .ResumedLogs}}{{if $i}}, {{end}}{{$log}}{{end}}</small></p>{{end}}
+{{if .ErroredRequests}}<table>
+<tr><th>ID</th><th>Latence (ms)</th><th>Access log</th><th>Erreur</th></tr>
+{{range .ErroredRequests}}<tr><td><code>{{.ID}}</code></td><td class="num">{{.LatencyMs}}</td>
+<td>{{with .Access}}{{.LogID}}: {{.Message}}{{else}}-{{end}}</td><td>{{.Error.LogID}}: {{.Error.Message}}</td></tr>
+{{end}}</table>{{end}}{{end}}
+
+<h2>Fichiers</h2>
+<p class="legend">
+<span><i style="background: #90a4ae"></i>DEBUG</span><span><i style="background: #1e88e5"></i>INFO</span>
+<span><i style="background: #fbc02d"></i>WARN</span><span><i style="background: #e53935"></i>ERROR</span>
+<span><i style="background: #6a1b9a"></i>FATAL</span>
+</p>
+<table>
+<tr><th>Log</th><th>Fichier</th><th>Status</th><th>Lignes</th><th>Rejetées</th><th>Niveaux</th><th>Message</th></tr>
+{{range .Rows}}<tr>
+<td>{{.Result.LogID}}{{if .Result.SourceID}}<br><small>{{.Result.SourceID}}</small>{{end}}</td>
+<td>{{.Result.FilePath}}</td>
+<td class="{{.Result.Status}}">{{.Result.Status}}</td>
+<td class="num">{{.Result.TotalLines}}</td>
+<td class="num">{{.Result.UnparsedLines}}</td>
+<td><div class="bar">{{range .Bar}}<div style="width: {{.Width}}%; background: {{.Color}}" title="{{.Label}}: {{.Count}}"></div>{{end}}</div>
+<small>W={{.Levels.Warn}} E={{.Levels.Error}} F={{.Levels.Fatal}}</small></td>
+<td>{{.Result.Message}}{{if .Result.ErrorDetails}}<br><small>{{.Result.ErrorDetails}}</small>{{end}}</td>
+</tr>
+{{end}}</table>
+
+{{range .Rows}}{{if or .Result.TopErrors .Result.Clusters .Result.Timeline .Result.Access .Result.Anomalies}}
+<h3>{{.Result.LogID}}</h3>
+{{if .Result.Anomalies}}<ul class="anomalies">{{range .Result.Anomalies}}<li>{{.Message}}</li>
+{{end}}</ul>{{end}}
+{{with .Result.Timeline}}<p>Activité par {{.Bucket}} depuis {{.Start.Format "2006-01-02 15:04"}} UTC</p>{{end}}
+{{if .ActivityLine}}<pre class="spark">lignes  |{{.ActivityLine}}|
+erreurs |{{.ErrorLine}}|</pre>{{end}}
+{{if .Result.TopErrors}}<table>
+<tr><th>Erreur fréquente</th><th>Nombre</th></tr>
+{{range .Result.TopErrors}}<tr><td>{{.Value}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Result.Clusters}}<table>
+<tr><th>Motif ({{.Result.DistinctTemplates}} distincts)</th><th>Niveau</th><th>Nombre</th><th>Exemple</th></tr>
+{{range .Result.Clusters}}<tr><td><code>{{.Template}}</code></td><td>{{.Level}}</td><td class="num">{{.Count}}</td><td><small>{{.Sample}}</small></td></tr>
+{{end}}</table>{{end}}
+{{with .Result.Access}}<p>Trafic HTTP: {{.Requests}} requêtes, {{.BytesServed}} bytes servis</p>{{end}}
+{{if .Traffic}}<div class="bar" style="width: 520px">{{range .Traffic}}<div style="width: {{.Width}}%; background: {{.Color}}" title="{{.Label}}: {{.Count}}"></div>{{end}}</div>
+<p class="legend">{{range .Traffic}}<span><i style="background: {{.Color}}"></i>{{.Label}}: {{.Count}}</span>{{end}}</p>{{end}}
+{{with .Result.Access}}<table>
+<tr><th>Chemin</th><th>Requêtes</th></tr>
+{{range .TopPaths}}<tr><td>{{.Value}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+<table>
+<tr><th>Client</th><th>Requêtes</th></tr>
+{{range .TopClients}}<tr><td>{{.Value}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// writeHTML génère un rapport autonome (CSS inline, pas de dépendance externe)
+func writeHTML(w io.Writer, report *Report) error {
+	summary := report.Summary
+	page := htmlReport{
+		Report: report,
+		StatusBar: makeBar([]barSegment{
+			{Label: config.StatusOK, Count: summary.OK, Color: colorOK},
+			{Label: config.StatusFailed, Count: summary.Failed, Color: colorFailed},
+			{Label: config.StatusTimeout, Count: summary.Timeout, Color: colorTimeout},
+		}),
+	}
+
+	for _, result := range report.Results {
+		levels := levelsOf(result)
+		row := htmlRow{
+			Result: result,
+			Levels: levels,
+			Bar: makeBar([]barSegment{
+				{Label: "DEBUG", Count: levels.Debug, Color: colorDebug},
+				{Label: "INFO", Count: levels.Info, Color: colorInfo},
+				{Label: "WARN", Count: levels.Warn, Color: colorWarn},
+				{Label: "ERROR", Count: levels.Error, Color: colorError},
+				{Label: "FATAL", Count: levels.Fatal, Color: colorFatal},
+			}),
+		}
+		if timeline := result.Timeline; timeline != nil {
+			row.ActivityLine = Sparkline(timeline.Lines, sparklineWidth)
+			row.ErrorLine = Sparkline(timeline.Errors, sparklineWidth)
+		}
+		if access := result.Access; access != nil {
+			classes := access.StatusClasses
+			row.Traffic = makeBar([]barSegment{
+				{Label: "2xx", Count: classes.Success, Color: colorOK},
+				{Label: "3xx", Count: classes.Redirection, Color: colorInfo},
+				{Label: "4xx", Count: classes.ClientError, Color: colorWarn},
+				{Label: "5xx", Count: classes.ServerError, Color: colorError},
+			})
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	return htmlTemplate.Execute(w, page)
+}
+
+// makeBar calcule la largeur de chaque segment
+func makeBar(segments []barSegment) []barSegment {
+	total := 0
+	for _, segment := range segments {
+		total += segment.Count
+	}
+	if total == 0 {
+		return nil
+	}
+	for i := range segments {
+		segments[i].Width = float64(segments[i].Count) * 100 / float64(total)
+	}
+	return segments
+}
@@ -0,0 +1,179 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// MetricsSnapshot est l'état exposé sur /metrics: dernier run et compteurs du serveur
+type MetricsSnapshot struct {
+	Configs    []config.LogConfig // dans le même ordre que Results
+	Results    []config.AnalysisResult
+	Alerts     []config.Alert
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	Runs         int // runs terminés depuis le démarrage
+	ConfigErrors int // rechargements de config en échec
+}
+
+// metric est une famille de métriques au format texte Prometheus
+type metric struct {
+	name    string
+	help    string
+	kind    string // gauge ou counter
+	samples []sample
+}
+
+type sample struct {
+	labels []string // paires nom, valeur
+	value  float64
+}
+
+func (m *metric) add(value float64, labels ...string) {
+	m.samples = append(m.samples, sample{labels: labels, value: value})
+}
+
+// WriteMetrics écrit le snapshot au format d'exposition texte de Prometheus (0.0.4)
+func WriteMetrics(w io.Writer, snapshot *MetricsSnapshot) error {
+	var b strings.Builder
+	for _, m := range buildMetrics(snapshot) {
+		if len(m.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, s := range m.samples {
+			b.WriteString(m.name)
+			writeLabels(&b, s.labels)
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(s.value, 'f', -1, 64))
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func buildMetrics(snapshot *MetricsSnapshot) []*metric {
+	runs := &metric{name: "loganalyzer_runs_total", help: "Nombre d'analyses terminées depuis le démarrage", kind: "counter"}
+	configErrors := &metric{name: "loganalyzer_config_errors_total", help: "Nombre de chargements de config en échec", kind: "counter"}
+	lastRun := &metric{name: "loganalyzer_last_run_timestamp_seconds", help: "Fin de la dernière analyse (timestamp Unix)", kind: "gauge"}
+	runDuration := &metric{name: "loganalyzer_last_run_duration_seconds", help: "Durée de la dernière analyse", kind: "gauge"}
+
+	runs.add(float64(snapshot.Runs))
+	configErrors.add(float64(snapshot.ConfigErrors))
+	if !snapshot.FinishedAt.IsZero() {
+		lastRun.add(float64(snapshot.FinishedAt.UnixMilli()) / 1000)
+		runDuration.add(snapshot.FinishedAt.Sub(snapshot.StartedAt).Seconds())
+	}
+
+	up := &metric{name: "loganalyzer_log_up", help: "1 si le dernier run a analysé le log (status OK)", kind: "gauge"}
+	status := &metric{name: "loganalyzer_log_status", help: "Status du dernier run (1 pour le status courant)", kind: "gauge"}
+	lines := &metric{name: "loganalyzer_log_lines", help: "Lignes lues", kind: "gauge"}
+	parsed := &metric{name: "loganalyzer_log_parsed_lines", help: "Lignes reconnues par le parser", kind: "gauge"}
+	unparsed := &metric{name: "loganalyzer_log_unparsed_lines", help: "Lignes rejetées par le parser", kind: "gauge"}
+	levels := &metric{name: "loganalyzer_log_level_lines", help: "Lignes par niveau", kind: "gauge"}
+	errorsMetric := &metric{name: "loganalyzer_log_errors", help: "Lignes ERROR et FATAL", kind: "gauge"}
+	size := &metric{name: "loganalyzer_log_size_bytes", help: "Taille du fichier sur disque", kind: "gauge"}
+	uncompressed := &metric{name: "loganalyzer_log_uncompressed_bytes", help: "Taille après décompression", kind: "gauge"}
+	duration := &metric{name: "loganalyzer_log_analysis_duration_seconds", help: "Durée de la dernière analyse du log", kind: "gauge"}
+	lastSeen := &metric{name: "loganalyzer_log_last_seen_timestamp_seconds", help: "Date de la dernière ligne datée (timestamp Unix)", kind: "gauge"}
+	requests := &metric{name: "loganalyzer_http_requests", help: "Requêtes HTTP par classe de status (access logs)", kind: "gauge"}
+	served := &metric{name: "loganalyzer_http_served_bytes", help: "Bytes servis (access logs)", kind: "gauge"}
+	alerts := &metric{name: "loganalyzer_alert_firing", help: "Règles d'alerte déclenchées au dernier run", kind: "gauge"}
+
+	for i, result := range snapshot.Results {
+		logType := ""
+		if i < len(snapshot.Configs) {
+			logType = snapshot.Configs[i].Type
+		}
+		labels := []string{"log_id", result.LogID, "type", logType}
+		with := func(name, value string) []string {
+			return append(append([]string(nil), labels...), name, value)
+		}
+
+		if result.Status == config.StatusOK {
+			up.add(1, labels...)
+		} else {
+			up.add(0, labels...)
+		}
+		for _, value := range []string{config.StatusOK, config.StatusFailed, config.StatusTimeout} {
+			status.add(boolValue(result.Status == value), with("status", value)...)
+		}
+		duration.add(float64(result.DurationMs)/1000, labels...)
+		if result.Status != config.StatusOK {
+			continue
+		}
+
+		lines.add(float64(result.TotalLines), labels...)
+		parsed.add(float64(result.ParsedLines), labels...)
+		unparsed.add(float64(result.UnparsedLines), labels...)
+		size.add(float64(result.CompressedSize), labels...)
+		uncompressed.add(float64(result.UncompressedSize), labels...)
+		if counts := result.Levels; counts != nil {
+			levels.add(float64(counts.Debug), with("level", "debug")...)
+			levels.add(float64(counts.Info), with("level", "info")...)
+			levels.add(float64(counts.Warn), with("level", "warn")...)
+			levels.add(float64(counts.Error), with("level", "error")...)
+			levels.add(float64(counts.Fatal), with("level", "fatal")...)
+			errorsMetric.add(float64(counts.Error+counts.Fatal), labels...)
+		}
+		if result.LastSeen != nil {
+			lastSeen.add(float64(result.LastSeen.UnixMilli())/1000, labels...)
+		}
+		if access := result.Access; access != nil {
+			classes := access.StatusClasses
+			requests.add(float64(classes.Informational), with("class", "1xx")...)
+			requests.add(float64(classes.Success), with("class", "2xx")...)
+			requests.add(float64(classes.Redirection), with("class", "3xx")...)
+			requests.add(float64(classes.ClientError), with("class", "4xx")...)
+			requests.add(float64(classes.ServerError), with("class", "5xx")...)
+			served.add(float64(access.BytesServed), labels...)
+		}
+	}
+
+	// Une série par couple règle/log, triée pour une sortie stable
+	sorted := append([]config.Alert(nil), snapshot.Alerts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Rule != sorted[j].Rule {
+			return sorted[i].Rule < sorted[j].Rule
+		}
+		return sorted[i].LogID < sorted[j].LogID
+	})
+	for _, alert := range sorted {
+		alerts.add(1, "rule", alert.Rule, "log_id", alert.LogID)
+	}
+
+	return []*metric{runs, configErrors, lastRun, runDuration,
+		up, status, lines, parsed, unparsed, levels, errorsMetric, size, uncompressed,
+		duration, lastSeen, requests, served, alerts}
+}
+
+// writeLabels écrit {nom="valeur",...} en échappant \, " et les retours à la ligne
+func writeLabels(b *strings.Builder, labels []string) {
+	if len(labels) == 0 {
+		return
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(b, `%s="%s"`, labels[i], escaper.Replace(labels[i+1]))
+	}
+	b.WriteByte('}')
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	started := time.Date(2025, 9, 24, 14, 0, 0, 0, time.UTC)
+	web := okResult("web", 2, 10, 154)
+	web.DurationMs = 250
+	web.Access = &config.AccessStats{Requests: 10, StatusClasses: config.StatusClassCounts{Success: 8, ServerError: 2}, BytesServed: 512}
+
+	snapshot := &MetricsSnapshot{
+		Configs: []config.LogConfig{
+			{ID: "web", Type: config.TypeNginxAccess},
+			{ID: `c:\logs "app"` + "\nbis", Type: config.TypeGeneric},
+		},
+		Results: []config.AnalysisResult{
+			web,
+			{LogID: `c:\logs "app"` + "\nbis", Status: config.StatusFailed, TotalLines: 3},
+		},
+		Alerts: []config.Alert{
+			{Rule: "z", LogID: "web"},
+			{Rule: `a"b`, LogID: "web"},
+		},
+		StartedAt:  started,
+		FinishedAt: started.Add(1500 * time.Millisecond),
+		Runs:       3,
+	}
+
+	var b bytes.Buffer
+	if err := WriteMetrics(&b, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	output := b.String()
+
+	for _, want := range []string{
+		"# HELP loganalyzer_runs_total Nombre d'analyses terminées depuis le démarrage\n# TYPE loganalyzer_runs_total counter\nloganalyzer_runs_total 3\n",
+		"loganalyzer_config_errors_total 0\n",
+		"loganalyzer_last_run_timestamp_seconds 1758722401.5\n",
+		"loganalyzer_last_run_duration_seconds 1.5\n",
+		`loganalyzer_log_up{log_id="web",type="nginx-access"} 1` + "\n",
+		`loganalyzer_log_up{log_id="c:\\logs \"app\"\nbis",type="generic"} 0` + "\n",
+		`loganalyzer_log_status{log_id="c:\\logs \"app\"\nbis",type="generic",status="FAILED"} 1` + "\n",
+		`loganalyzer_log_status{log_id="web",type="nginx-access",status="TIMEOUT"} 0` + "\n",
+		`loganalyzer_log_analysis_duration_seconds{log_id="web",type="nginx-access"} 0.25` + "\n",
+		`loganalyzer_log_level_lines{log_id="web",type="nginx-access",level="error"} 2` + "\n",
+		`loganalyzer_log_errors{log_id="web",type="nginx-access"} 2` + "\n",
+		`loganalyzer_http_requests{log_id="web",type="nginx-access",class="5xx"} 2` + "\n",
+		`loganalyzer_http_served_bytes{log_id="web",type="nginx-access"} 512` + "\n",
+		// Alertes triées par règle
+		`loganalyzer_alert_firing{rule="a\"b",log_id="web"} 1` + "\n" + `loganalyzer_alert_firing{rule="z",log_id="web"} 1` + "\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("métriques sans %q:\n%s", want, output)
+		}
+	}
+
+	// Pas de compteurs de lignes pour un log en échec, pas de métrique vide
+	if strings.Contains(output, `loganalyzer_log_lines{log_id="c:`) {
+		t.Error("lignes exposées pour un log en échec")
+	}
+	if strings.Contains(output, "loganalyzer_log_last_seen_timestamp_seconds") {
+		t.Error("famille sans échantillon exposée")
+	}
+
+	// Chaque échantillon tient sur une ligne (retours à la ligne échappés)
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if !strings.HasPrefix(line, "# ") && !strings.HasPrefix(line, "loganalyzer_") {
+			t.Errorf("ligne invalide %q", line)
+		}
+	}
+}
+
+func TestWriteMetricsBeforeFirstRun(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteMetrics(&b, &MetricsSnapshot{}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "last_run") || !strings.Contains(b.String(), "loganalyzer_runs_total 0\n") {
+		t.Errorf("métriques avant le premier run:\n%s", b.String())
+	}
+}
@@ -0,0 +1,120 @@
+package reporter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// Version du schéma du rapport exporté. À incrémenter si un champ change de sens
+// ou disparaît; l'ajout d'un champ optionnel ne change pas la version.
+const SchemaVersion = 1
+
+// Report est l'enveloppe d'un rapport exporté: métadonnées du run + résultats
+type Report struct {
+	SchemaVersion int       `json:"schema_version"`
+	RunID         string    `json:"run_id"`
+	StartedAt     time.Time `json:"started_at"`
+	FinishedAt    time.Time `json:"finished_at"`
+	DurationMs    int64     `json:"duration_ms"`
+	Hostname      string    `json:"hostname"`
+	ToolVersion   string    `json:"tool_version"`
+	ConfigPath    string    `json:"config_path"`
+	ConfigHash    string    `json:"config_hash"`
+	Interrupted   bool      `json:"interrupted,omitempty"`
+
+	// Filtres de la ligne de commande (--only, --tag, --type)
+	Filters *config.Filter `json:"filters,omitempty"`
+	// Fenêtre --since/--until
+	Window *config.TimeWindow `json:"window,omitempty"`
+
+	Summary Summary                 `json:"summary"`
+	Alerts  []config.Alert          `json:"alerts,omitempty"`
+	Results []config.AnalysisResult `json:"results"`
+
+	// Corrélation entre fichiers (section "correlation" de la config)
+	Correlation *config.CorrelationReport `json:"correlation,omitempty"`
+}
+
+// Summary compte les résultats par status
+type Summary struct {
+	Total   int `json:"total"`
+	OK      int `json:"ok"`
+	Failed  int `json:"failed"`
+	Timeout int `json:"timeout"`
+}
+
+// RunInfo décrit l'exécution qui a produit les résultats
+type RunInfo struct {
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	ToolVersion string
+	ConfigPath  string
+	Interrupted bool
+	Filters     config.Filter
+	Window      config.TimeWindow
+	Correlation *config.CorrelationReport
+}
+
+// NewReport construit l'enveloppe; le hash de la config est calculé ici
+func NewReport(results []config.AnalysisResult, alerts []config.Alert, run RunInfo) *Report {
+	hostname, _ := os.Hostname()
+	configHash, _ := config.FileHash(run.ConfigPath)
+
+	var filters *config.Filter
+	if !run.Filters.IsEmpty() {
+		filters = &run.Filters
+	}
+
+	var window *config.TimeWindow
+	if !run.Window.IsZero() {
+		window = &run.Window
+	}
+
+	return &Report{
+		SchemaVersion: SchemaVersion,
+		RunID:         newRunID(),
+		StartedAt:     run.StartedAt,
+		FinishedAt:    run.FinishedAt,
+		DurationMs:    run.FinishedAt.Sub(run.StartedAt).Milliseconds(),
+		Hostname:      hostname,
+		ToolVersion:   run.ToolVersion,
+		ConfigPath:    run.ConfigPath,
+		ConfigHash:    configHash,
+		Interrupted:   run.Interrupted,
+		Filters:       filters,
+		Window:        window,
+		Summary:       Summarize(results),
+		Alerts:        alerts,
+		Results:       results,
+		Correlation:   run.Correlation,
+	}
+}
+
+// Summarize compte les succès, échecs et timeouts
+func Summarize(results []config.AnalysisResult) Summary {
+	summary := Summary{Total: len(results)}
+	for _, result := range results {
+		switch result.Status {
+		case config.StatusFailed:
+			summary.Failed++
+		case config.StatusTimeout:
+			summary.Timeout++
+		default:
+			summary.OK++
+		}
+	}
+	return summary
+}
+
+// newRunID génère un identifiant aléatoire de 16 caractères hexa
+func newRunID() string {
+	buffer := make([]byte, 8)
+	if _, err := rand.Read(buffer); err != nil {
+		return time.Now().Format("20060102150405")
+	}
+	return hex.EncodeToString(buffer)
+}
@@ -1,36 +1,69 @@
 package reporter
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/axellelanca/go_loganizer/internal/config"
 )
 
-// ExportResults sauve les résultats en JSON
-func ExportResults(results []config.AnalysisResult, outputPath string) error {
+// ExportReport sauve le rapport dans le format demandé
+// (json, ndjson, csv, markdown, html, junit); format vide = déduit de l'extension
+func ExportReport(report *Report, outputPath string, format string) error {
+	if format == "" {
+		format = FormatFromPath(outputPath)
+	}
+	writer, ok := formatWriters[format]
+	if !ok {
+		return fmt.Errorf("format inconnu %q (formats: %s)", format, strings.Join(Formats(), ", "))
+	}
+
 	// Créer les dossiers si besoin
 	if err := createDirectoriesIfNeeded(outputPath); err != nil {
 		return fmt.Errorf("impossible de créer les dossiers: %w", err)
 	}
 
-	// Convertir en JSON avec indentation
-	jsonData, err := json.MarshalIndent(results, "", "  ")
-	if err != nil {
-		return fmt.Errorf("erreur sérialisation JSON: %w", err)
+	// Sérialiser dans le format choisi
+	var buffer bytes.Buffer
+	if err := writer(&buffer, report); err != nil {
+		return fmt.Errorf("erreur sérialisation %s: %w", format, err)
 	}
 
 	// Écrire le fichier
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
+	if err := writeFileAtomic(outputPath, buffer.Bytes()); err != nil {
 		return fmt.Errorf("erreur écriture fichier: %w", err)
 	}
 
 	return nil
 }
 
+// writeFileAtomic écrit dans un fichier temporaire puis le renomme,
+// un export interrompu ne laisse donc jamais de rapport tronqué
+func writeFileAtomic(outputPath string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), outputPath)
+}
+
 // GenerateTimestampedFilename ajoute la date au nom de fichier
 func GenerateTimestampedFilename(basePath string) string {
 	now := time.Now()
@@ -55,30 +88,200 @@ func createDirectoriesIfNeeded(filePath string) error {
 }
 
 // PrintResults affiche un résumé des résultats sur la console
-func PrintResults(results []config.AnalysisResult) {
+func PrintResults(results []config.AnalysisResult, alerts []config.Alert) {
 	fmt.Println("\n=== RÉSUMÉ DE L'ANALYSE ===")
 	fmt.Printf("Total de fichiers analysés: %d\n\n", len(results))
 
-	successCount := 0
-	failedCount := 0
-
 	for _, result := range results {
-		if result.Status == config.StatusFailed {
-			failedCount++
-		} else {
-			successCount++
-		}
 
 		fmt.Printf("[%s] %s\n", result.LogID, result.FilePath)
+		if result.SourceID != "" {
+			fmt.Printf("   Source: %s\n", result.SourceID)
+		}
 		fmt.Printf("   Status: %s\n", result.Status)
 		fmt.Printf("   Message: %s\n", result.Message)
 		
 		if result.ErrorDetails != "" {
-			fmt.Printf("   Erreur: %s\n", result.ErrorDetails)
+			fmt.Printf("   Erreur: %s\n", errorText(result))
 		}
+		for _, parseErr := range result.ParseErrors {
+			fmt.Printf("   Ligne rejetée: %s\n", parseErr)
+		}
+		printLevelStats(result)
+		printTimeline(result)
+		printClusters(result)
+		printAnomalies(result)
+		printAccessStats(result)
 		fmt.Println()
 	}
 
 	fmt.Printf("=== BILAN ===\n")
-	fmt.Printf("Succès: %d | Échecs: %d\n", successCount, failedCount)
-}
\ No newline at end of file
+	summary := Summarize(results)
+	fmt.Printf("Succès: %d | Échecs: %d | Timeouts: %d\n", summary.OK, summary.Failed, summary.Timeout)
+	printAlerts(alerts)
+}
+
+// printAlerts liste les règles déclenchées
+func printAlerts(alerts []config.Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+	fmt.Printf("\n=== ALERTES (%d) ===\n", len(alerts))
+	for _, alert := range alerts {
+		fmt.Printf("[%s] %s: %s = %s\n", alert.Rule, alert.LogID, alert.Condition, alert.Value)
+	}
+}
+
+// PrintCorrelation affiche les requêtes en erreur suivies entre les logs
+func PrintCorrelation(report *config.CorrelationReport) {
+	if report == nil {
+		return
+	}
+	fmt.Printf("\n=== CORRÉLATION (%s) ===\n", report.Field)
+	fmt.Println(report.String())
+	if report.Dropped > 0 {
+		fmt.Printf("%d IDs ignorés (limite mémoire atteinte)\n", report.Dropped)
+	}
+	if len(report.ResumedLogs) > 0 {
+		fmt.Printf("Repris depuis un checkpoint (nouvelles lignes seulement, chaînes des runs précédents coupées): %s\n",
+			strings.Join(report.ResumedLogs, ", "))
+	}
+	for _, request := range report.ErroredRequests {
+		fmt.Printf("[%s] %d ms, %d erreurs, logs: %s\n",
+			request.ID, request.LatencyMs, request.Errors, strings.Join(request.Logs, ", "))
+		if request.Access != nil {
+			fmt.Printf("   access  %s: %s\n", request.Access.LogID, request.Access.Message)
+		} else {
+			fmt.Println("   access  (aucune entrée)")
+		}
+		fmt.Printf("   %-7s %s: %s\n", request.Error.Level, request.Error.LogID, request.Error.Message)
+	}
+}
+
+// printLevelStats affiche les niveaux, la période couverte et les erreurs fréquentes
+func printLevelStats(result config.AnalysisResult) {
+	if result.Levels != nil {
+		levels := result.Levels
+		fmt.Printf("   Niveaux: DEBUG=%d INFO=%d WARN=%d ERROR=%d FATAL=%d\n",
+			levels.Debug, levels.Info, levels.Warn, levels.Error, levels.Fatal)
+	}
+	if result.FirstSeen != nil && result.LastSeen != nil {
+		fmt.Printf("   Période: %s -> %s\n",
+			result.FirstSeen.Format(time.RFC3339), result.LastSeen.Format(time.RFC3339))
+	}
+	if len(result.TopErrors) > 0 {
+		fmt.Println("   Erreurs les plus fréquentes:")
+		for _, entry := range result.TopErrors {
+			fmt.Printf("     %4d x %s\n", entry.Count, entry.Value)
+		}
+	}
+}
+
+// printTimeline affiche l'activité et les erreurs en sparkline
+func printTimeline(result config.AnalysisResult) {
+	timeline := result.Timeline
+	if timeline == nil {
+		return
+	}
+	perColumn := (len(timeline.Lines) + sparklineWidth - 1) / sparklineWidth
+	unit := timeline.Bucket
+	if perColumn > 1 {
+		unit = fmt.Sprintf("%d %s", perColumn, timeline.Bucket)
+	}
+	fmt.Printf("   Activité (1 colonne = %s, depuis %s):\n", unit, timeline.Start.Format(time.RFC3339))
+	fmt.Printf("     lignes  |%s| max %d\n", Sparkline(timeline.Lines, sparklineWidth), maxValue(downsample(timeline.Lines, sparklineWidth)))
+	fmt.Printf("     erreurs |%s| max %d\n", Sparkline(timeline.Errors, sparklineWidth), maxValue(downsample(timeline.Errors, sparklineWidth)))
+}
+
+// errorText préfixe le détail de l'erreur par son code
+func errorText(result config.AnalysisResult) string {
+	if result.ErrorCode == "" {
+		return result.ErrorDetails
+	}
+	return fmt.Sprintf("[%s] %s", result.ErrorCode, result.ErrorDetails)
+}
+
+// printAnomalies affiche les écarts à la baseline
+func printAnomalies(result config.AnalysisResult) {
+	if len(result.Anomalies) == 0 {
+		return
+	}
+	fmt.Printf("   Anomalies (%d):\n", len(result.Anomalies))
+	for _, anomaly := range result.Anomalies {
+		fmt.Printf("     ! %s\n", anomaly.Message)
+	}
+}
+
+// printClusters affiche les motifs de messages les plus fréquents
+func printClusters(result config.AnalysisResult) {
+	if len(result.Clusters) == 0 {
+		return
+	}
+	fmt.Printf("   Motifs les plus fréquents (%d motifs distincts):\n", result.DistinctTemplates)
+	for _, cluster := range result.Clusters {
+		level := cluster.Level
+		if level == "" {
+			level = "-"
+		}
+		fmt.Printf("     %4d x [%s] %s\n", cluster.Count, level, cluster.Template)
+		fmt.Printf("            ex: %s\n", cluster.Sample)
+	}
+}
+
+// printAccessStats affiche la synthèse du trafic HTTP
+func printAccessStats(result config.AnalysisResult) {
+	access := result.Access
+	if access == nil {
+		return
+	}
+
+	classes := access.StatusClasses
+	fmt.Printf("   Trafic HTTP: %d requêtes, %d bytes servis\n", access.Requests, access.BytesServed)
+	fmt.Printf("   Codes: 2xx=%d 3xx=%d 4xx=%d 5xx=%d\n",
+		classes.Success, classes.Redirection, classes.ClientError, classes.ServerError)
+
+	methods := make([]string, 0, len(access.Methods))
+	for method := range access.Methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for i, method := range methods {
+		methods[i] = fmt.Sprintf("%s=%d", method, access.Methods[method])
+	}
+	fmt.Printf("   Méthodes: %s\n", strings.Join(methods, " "))
+
+	fmt.Println("   Chemins les plus demandés:")
+	for _, entry := range access.TopPaths {
+		fmt.Printf("     %4d x %s\n", entry.Count, entry.Value)
+	}
+	fmt.Println("   Clients les plus actifs:")
+	for _, entry := range access.TopClients {
+		fmt.Printf("     %4d x %s\n", entry.Count, entry.Value)
+	}
+}
+
+// PrintWatch efface l'écran et affiche un tableau compact (mode watch)
+func PrintWatch(results []config.AnalysisResult, alerts []config.Alert, now time.Time) {
+	fmt.Print("\033[H\033[2J")
+	fmt.Printf("=== SUIVI DES LOGS - %s (Ctrl-C pour arrêter) ===\n\n", now.Format("15:04:05"))
+	fmt.Printf("%-30s %-7s %9s %9s %7s %7s  %s\n",
+		"LOG", "STATUS", "LIGNES", "REJETÉES", "WARN", "ERROR", "DERNIÈRE LIGNE")
+
+	for _, result := range results {
+		warn, errs := 0, 0
+		if result.Levels != nil {
+			warn = result.Levels.Warn
+			errs = result.Levels.Error + result.Levels.Fatal
+		}
+		lastSeen := "-"
+		if result.LastSeen != nil {
+			lastSeen = result.LastSeen.Format(time.RFC3339)
+		}
+		fmt.Printf("%-30s %-7s %9d %9d %7d %7d  %s\n",
+			result.LogID, result.Status, result.TotalLines, result.UnparsedLines, warn, errs, lastSeen)
+		if result.ErrorDetails != "" {
+			fmt.Printf("   %s\n   Erreur: %s\n", result.Message, errorText(result))
+		}
+	}
+	printAlerts(alerts)
+}
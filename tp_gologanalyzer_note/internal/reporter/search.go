@@ -0,0 +1,81 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/axellelanca/go_loganizer/internal/config"
+)
+
+// SearchReport est la sortie JSON de la commande search
+type SearchReport struct {
+	Pattern string                `json:"pattern"`
+	Matches int                   `json:"matches"`
+	Results []config.SearchResult `json:"results"`
+}
+
+// NewSearchReport compte les correspondances de tous les fichiers
+func NewSearchReport(pattern string, results []config.SearchResult) *SearchReport {
+	report := &SearchReport{Pattern: pattern, Results: results}
+	for _, result := range results {
+		report.Matches += len(result.Matches)
+	}
+	return report
+}
+
+// WriteSearchJSON écrit la recherche en JSON indenté
+func WriteSearchJSON(w io.Writer, report *SearchReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
+
+// PrintSearch affiche les correspondances comme grep: "id:ligne:texte",
+// "id-ligne-texte" pour le contexte et "--" entre deux blocs
+func PrintSearch(w io.Writer, report *SearchReport) {
+	files := 0
+	printedBlock := false // un bloc d'un fichier précédent a été affiché
+	for _, result := range report.Results {
+		if result.Status != config.StatusOK {
+			fmt.Fprintf(w, "%s: %s - [%s] %s\n", result.LogID, result.Status, result.ErrorCode, result.ErrorDetails)
+			continue
+		}
+		if len(result.Matches) > 0 {
+			files++
+		}
+
+		lastPrinted := 0
+		for _, match := range result.Matches {
+			hasContext := len(match.Before) > 0 || len(match.After) > 0
+			firstLine := match.Line
+			if len(match.Before) > 0 {
+				firstLine = match.Before[0].Line
+			}
+			newBlock := (lastPrinted == 0 && printedBlock) || (lastPrinted > 0 && firstLine > lastPrinted+1)
+			if hasContext && newBlock {
+				fmt.Fprintln(w, "--")
+			}
+			printedBlock = true
+
+			for _, line := range match.Before {
+				fmt.Fprintf(w, "%s-%d-%s\n", result.LogID, line.Line, line.Text)
+			}
+			fmt.Fprintf(w, "%s:%d:%s\n", result.LogID, match.Line, match.Text)
+			for _, line := range match.After {
+				fmt.Fprintf(w, "%s-%d-%s\n", result.LogID, line.Line, line.Text)
+			}
+
+			lastPrinted = match.Line
+			if len(match.After) > 0 {
+				lastPrinted = match.After[len(match.After)-1].Line
+			}
+		}
+		if result.Truncated {
+			fmt.Fprintf(w, "%s: limite de correspondances atteinte, suite non lue\n", result.LogID)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d correspondance(s) dans %d fichier(s) sur %d\n", report.Matches, files, len(report.Results))
+}
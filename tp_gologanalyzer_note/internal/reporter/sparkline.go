@@ -0,0 +1,56 @@
+package reporter
+
+import "strings"
+
+// Largeur max d'une sparkline affichée
+const sparklineWidth = 60
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline dessine les valeurs avec des blocs ▁..█. Au-delà de width valeurs,
+// les tranches voisines sont additionnées. Une valeur nulle reste un espace.
+func Sparkline(values []int, width int) string {
+	values = downsample(values, width)
+	max := maxValue(values)
+
+	var b strings.Builder
+	for _, value := range values {
+		switch {
+		case value == 0:
+			b.WriteRune(' ')
+		case max == 0:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			b.WriteRune(sparkBlocks[(value*(len(sparkBlocks)-1)+max-1)/max])
+		}
+	}
+	return b.String()
+}
+
+// downsample regroupe les valeurs par paquets pour tenir dans width colonnes
+func downsample(values []int, width int) []int {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+	group := (len(values) + width - 1) / width
+	grouped := make([]int, 0, width)
+	for i := 0; i < len(values); i += group {
+		sum := 0
+		for _, value := range values[i:min(i+group, len(values))] {
+			sum += value
+		}
+		grouped = append(grouped, sum)
+	}
+	return grouped
+}
+
+// maxValue renvoie la plus grande valeur
+func maxValue(values []int) int {
+	max := 0
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	return max
+}
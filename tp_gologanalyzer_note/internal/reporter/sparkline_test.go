@@ -0,0 +1,29 @@
+package reporter
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		width  int
+		want   string
+	}{
+		{name: "vide", values: nil, width: 10, want: ""},
+		{name: "zéros", values: []int{0, 0}, width: 10, want: "  "},
+		{name: "échelle", values: []int{1, 2, 4, 8}, width: 10, want: "▂▃▅█"},
+		{name: "valeur nulle en espace", values: []int{5, 0, 5}, width: 10, want: "█ █"},
+		{name: "plus petite valeur visible", values: []int{1, 1000}, width: 10, want: "▂█"},
+		{name: "regroupement", values: []int{1, 1, 0, 0, 2, 2}, width: 3, want: "▅ █"},
+		{name: "regroupement incomplet", values: []int{4, 4, 4, 4, 1}, width: 2, want: "█▄"},
+		{name: "sans limite", values: []int{1, 2, 3}, width: 0, want: "▄▆█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("Sparkline(%v, %d) = %q, attendu %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}